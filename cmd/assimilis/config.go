@@ -0,0 +1,20 @@
+package main
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/traefik/assimilis/v2/pkg/generator"
+	"gopkg.in/yaml.v3"
+)
+
+func printConfig(w io.Writer, cfg generator.Config) error {
+	out, err := yaml.Marshal(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to marshal configuration: %w", err)
+	}
+
+	_, err = w.Write(out)
+
+	return err
+}
@@ -15,8 +15,22 @@ import (
 	"github.com/urfave/cli/v3"
 )
 
+const (
+	configFlag   = "config"
+	envVarPrefix = "ASSIMILIS_"
+)
+
 func main() {
+	// Settings are applied in order: defaults, config file, environment
+	// variables, flags.
 	cfg := generator.DefaultConfig()
+
+	if path := configFilePath(os.Args[1:]); path != "" {
+		if err := generator.LoadConfigFile(path, &cfg); err != nil {
+			log.Fatal().Err(err).Msg("Invalid configuration file")
+		}
+	}
+
 	app := &cli.Command{
 		Name:  "assimilis",
 		Usage: "Generate OSS attribution files",
@@ -26,6 +40,17 @@ func main() {
 				Usage:  "Display version information",
 				Action: displayVersion,
 			},
+			{
+				Name:  "config",
+				Usage: "Manage the configuration",
+				Commands: []*cli.Command{
+					{
+						Name:   "print",
+						Usage:  "Print the effective configuration",
+						Action: func(_ context.Context, _ *cli.Command) error { return printConfig(os.Stdout, cfg) },
+					},
+				},
+			},
 		},
 		Flags:  buildFlags(&cfg),
 		Action: func(ctx context.Context, _ *cli.Command) error { return run(ctx, cfg) },
@@ -44,11 +69,19 @@ func main() {
 	}
 }
 
+// buildFlags returns the flags, defaulting to the values of cfg. Every flag can
+// be set with an ASSIMILIS_* environment variable as well, e.g.
+// ASSIMILIS_REPO_NAME for --repo-name.
 func buildFlags(cfg *generator.Config) []cli.Flag {
-	return []cli.Flag{
+	return withEnvVars([]cli.Flag{
+		&cli.StringFlag{
+			Name:  configFlag,
+			Usage: "Path to the configuration file (default: assimilis.yaml at the repository root)",
+		},
 		&cli.StringFlag{
 			Name:        "repo-name",
 			Usage:       "Name of the repository",
+			Value:       cfg.RepoName,
 			Destination: &cfg.RepoName,
 		},
 		&cli.StringFlag{
@@ -66,13 +99,21 @@ func buildFlags(cfg *generator.Config) []cli.Flag {
 		&cli.StringFlag{
 			Name:        "html-template",
 			Usage:       "Override HTML template path (default: embedded)",
+			Value:       cfg.HTMLTemplatePath,
 			Destination: &cfg.HTMLTemplatePath,
 		},
 		&cli.StringFlag{
 			Name:        "notice-template",
 			Usage:       "Override NOTICE template path (default: embedded)",
+			Value:       cfg.NoticeTplPath,
 			Destination: &cfg.NoticeTplPath,
 		},
+		&cli.StringFlag{
+			Name:        "template-dir",
+			Usage:       "Directory of partial templates (*.gotpl) available to the HTML and NOTICE templates",
+			Value:       cfg.TemplateDir,
+			Destination: &cfg.TemplateDir,
+		},
 		&cli.StringFlag{
 			Name:        "spdx-version",
 			Usage:       "SPDX license-list-data version/tag",
@@ -91,32 +132,219 @@ func buildFlags(cfg *generator.Config) []cli.Flag {
 			Value:       cfg.NoticeFileName,
 			Destination: &cfg.NoticeFileName,
 		},
+		&cli.StringSliceFlag{
+			Name:  "output",
+			Usage: "Output to render, as TEMPLATE=PATH with a built-in template (" + strings.Join(generator.BuiltinTemplateNames(), ", ") + ") or a template file and a path relative to the output directory, can be repeated (default: the HTML and NOTICE files)",
+			Action: func(_ context.Context, _ *cli.Command, values []string) error {
+				// The flag overrides the outputs of the config file.
+				cfg.Outputs = nil
+
+				for _, value := range values {
+					o, err := generator.ParseOutput(value)
+					if err != nil {
+						return err
+					}
+
+					cfg.Outputs = append(cfg.Outputs, o)
+				}
+
+				return nil
+			},
+		},
+		&cli.StringFlag{
+			Name:        "org-data-dir",
+			Usage:       "Directory of organization-wide license-map.json, license-corrections.json, filters.json and curations.json, merged over the embedded ones",
+			Value:       cfg.OrgDataDir,
+			Destination: &cfg.OrgDataDir,
+		},
 		&cli.StringFlag{
 			Name:        "license-map",
-			Usage:       "Path to external license-map JSON (default: embedded)",
+			Usage:       "Path to a license-map JSON merged over the embedded and organization ones",
+			Value:       cfg.LicenseMapPath,
 			Destination: &cfg.LicenseMapPath,
+			Action:      clearInline(func() { cfg.LicenseMap = nil }),
 		},
 		&cli.StringFlag{
 			Name:        "license-corrections",
-			Usage:       "Path to external license-corrections JSON (default: embedded)",
+			Usage:       "Path to a license-corrections JSON merged over the embedded and organization ones",
+			Value:       cfg.LicenseCorrectionsPath,
 			Destination: &cfg.LicenseCorrectionsPath,
+			Action:      clearInline(func() { cfg.LicenseCorrections = nil }),
 		},
 		&cli.StringFlag{
 			Name:        "filters",
-			Usage:       "Path to external filters JSON (default: embedded)",
+			Usage:       "Path to a filters JSON merged over the embedded and organization ones",
+			Value:       cfg.FiltersPath,
 			Destination: &cfg.FiltersPath,
+			Action:      clearInline(func() { cfg.Filters = nil }),
+		},
+		&cli.StringFlag{
+			Name:        "curations",
+			Usage:       "Path to a curations JSON (name, URL, copyright, notes and modifications by PURL) merged over the embedded and organization ones",
+			Value:       cfg.CurationsPath,
+			Destination: &cfg.CurationsPath,
+			Action:      clearInline(func() { cfg.Curations = nil }),
+		},
+		&cli.StringFlag{
+			Name:        "extra-components",
+			Usage:       "Path to a JSON list of components missing from the SBOM, such as vendored sources, fonts or icons",
+			Value:       cfg.ExtraComponentsPath,
+			Destination: &cfg.ExtraComponentsPath,
+			Action:      clearInline(func() { cfg.ExtraComponents = nil }),
 		},
 		&cli.StringFlag{
 			Name:        "node-modules-dir",
 			Usage:       "Path to node_modules directory for npm copyright extraction (default: auto-detect)",
+			Value:       cfg.NodeModulesDir,
 			Destination: &cfg.NodeModulesDir,
 		},
-		&cli.StringFlag{
+		&cli.StringSliceFlag{
 			Name:        "python-site-packages-dir",
-			Usage:       "Path to Python site-packages directory for PyPI copyright extraction",
-			Destination: &cfg.PythonSitePackagesDir,
+			Usage:       "Path to a Python site-packages directory for PyPI copyright extraction, can be repeated (default: auto-detect)",
+			Value:       cfg.PythonSitePackagesDirs,
+			Destination: &cfg.PythonSitePackagesDirs,
+		},
+		&cli.StringFlag{
+			Name:        "cargo-vendor-dir",
+			Usage:       "Path to crates vendored with cargo vendor for Cargo copyright extraction (default: auto-detect)",
+			Value:       cfg.CargoVendorDir,
+			Destination: &cfg.CargoVendorDir,
+		},
+		&cli.StringFlag{
+			Name:        "maven-repository-dir",
+			Usage:       "Path to the local Maven repository for Maven copyright extraction (default: ~/.m2/repository)",
+			Value:       cfg.MavenRepositoryDir,
+			Destination: &cfg.MavenRepositoryDir,
+		},
+		&cli.StringFlag{
+			Name:        "composer-vendor-dir",
+			Usage:       "Path to the Composer vendor directory for Composer copyright extraction (default: auto-detect)",
+			Value:       cfg.ComposerVendorDir,
+			Destination: &cfg.ComposerVendorDir,
+		},
+		&cli.StringFlag{
+			Name:        "gem-home",
+			Usage:       "Path to the installed gems for RubyGems copyright extraction (default: $GEM_HOME or vendor/bundle)",
+			Value:       cfg.GemHome,
+			Destination: &cfg.GemHome,
 		},
+		&cli.StringFlag{
+			Name:        "rootfs",
+			Usage:       "Path to an extracted root filesystem for deb, apk and rpm copyright and license extraction",
+			Value:       cfg.RootFS,
+			Destination: &cfg.RootFS,
+		},
+		&cli.BoolFlag{
+			Name:        "detect-licenses",
+			Usage:       "Detect licenses from the package license files to fill missing SBOM licenses",
+			Value:       cfg.DetectLicenses,
+			Destination: &cfg.DetectLicenses,
+		},
+		&cli.FloatFlag{
+			Name:        "license-detection-threshold",
+			Usage:       "Minimum confidence (0-1) for a detected license to be used",
+			Value:       cfg.LicenseDetectionThreshold,
+			Destination: &cfg.LicenseDetectionThreshold,
+		},
+		&cli.BoolFlag{
+			Name:        "component-license-texts",
+			Usage:       "Use the license texts shipped by the components, grouping identical ones, instead of the SPDX texts",
+			Value:       cfg.ComponentLicenseTexts,
+			Destination: &cfg.ComponentLicenseTexts,
+		},
+		&cli.BoolFlag{
+			Name:        "prefer-declared-licenses",
+			Usage:       "Use the declared licenses instead of the concluded ones when the SBOM has both",
+			Value:       cfg.PreferDeclaredLicenses,
+			Destination: &cfg.PreferDeclaredLicenses,
+		},
+		&cli.StringSliceFlag{
+			Name:        "first-party-prefix",
+			Usage:       "PURL (pkg:...) or package name prefix of first-party components to exclude, can be repeated",
+			Value:       cfg.FirstPartyPrefixes,
+			Destination: &cfg.FirstPartyPrefixes,
+		},
+		&cli.StringSliceFlag{
+			Name:        "first-party-package-json",
+			Usage:       "Path to a package.json whose npm scope, or name when unscoped, identifies first-party components, can be repeated",
+			Value:       cfg.FirstPartyPackageJSONs,
+			Destination: &cfg.FirstPartyPackageJSONs,
+		},
+		&cli.BoolFlag{
+			Name:        "first-party-namespaces",
+			Usage:       "Also exclude the components sharing the namespace (npm scope, Maven group) or the supplier of the SBOM subject as first party",
+			Value:       cfg.FirstPartyNamespaces,
+			Destination: &cfg.FirstPartyNamespaces,
+		},
+		&cli.BoolFlag{
+			Name:        "disable-first-party-detection",
+			Usage:       "Do not exclude the first-party components detected from the SBOM metadata, go.mod and package.json",
+			Value:       cfg.DisableFirstPartyDetection,
+			Destination: &cfg.DisableFirstPartyDetection,
+		},
+	})
+}
+
+// clearInline returns a flag action calling reset, which clears the data given
+// inline in the config file: the flag overrides it.
+func clearInline(reset func()) func(context.Context, *cli.Command, string) error {
+	return func(context.Context, *cli.Command, string) error {
+		reset()
+
+		return nil
+	}
+}
+
+// withEnvVars sets the ASSIMILIS_* environment variable of each flag.
+func withEnvVars(flags []cli.Flag) []cli.Flag {
+	for _, flag := range flags {
+		switch f := flag.(type) {
+		case *cli.StringFlag:
+			f.Sources = cli.EnvVars(envVarName(f.Name))
+		case *cli.StringSliceFlag:
+			f.Sources = cli.EnvVars(envVarName(f.Name))
+		case *cli.BoolFlag:
+			f.Sources = cli.EnvVars(envVarName(f.Name))
+		case *cli.FloatFlag:
+			f.Sources = cli.EnvVars(envVarName(f.Name))
+		}
 	}
+
+	return flags
+}
+
+func envVarName(flagName string) string {
+	return envVarPrefix + strings.ToUpper(strings.ReplaceAll(flagName, "-", "_"))
+}
+
+// configFilePath returns the configuration file set by --config, then by
+// ASSIMILIS_CONFIG, or the one found at the repository root. It runs before the
+// flags are parsed, as the file provides their defaults.
+func configFilePath(args []string) string {
+	for i, arg := range args {
+		if arg == "--" {
+			break
+		}
+
+		name, value, hasValue := strings.Cut(strings.TrimLeft(arg, "-"), "=")
+		if !strings.HasPrefix(arg, "-") || name != configFlag {
+			continue
+		}
+
+		if hasValue {
+			return value
+		}
+
+		if i+1 < len(args) {
+			return args[i+1]
+		}
+	}
+
+	if path := os.Getenv(envVarName(configFlag)); path != "" {
+		return path
+	}
+
+	return generator.FindConfigFile()
 }
 
 func validate(cfg generator.Config) error {
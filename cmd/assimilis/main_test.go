@@ -1,10 +1,13 @@
 package main
 
 import (
+	"bytes"
+	"context"
 	"testing"
 
 	"github.com/stretchr/testify/require"
 	"github.com/traefik/assimilis/v2/pkg/generator"
+	"github.com/urfave/cli/v3"
 )
 
 func TestValidate_Fail(t *testing.T) {
@@ -26,3 +29,111 @@ func TestValidate_OK(t *testing.T) {
 	err := validate(cfg)
 	require.NoError(t, err)
 }
+
+func TestConfigFilePath(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		args     []string
+		env      string
+		expected string
+	}{
+		{
+			desc:     "flag",
+			args:     []string{"--repo-name", "traefik", "--config", "ci.yaml"},
+			env:      "env.yaml",
+			expected: "ci.yaml",
+		},
+		{
+			desc:     "flag with equal sign",
+			args:     []string{"-config=ci.yaml", "config", "print"},
+			expected: "ci.yaml",
+		},
+		{
+			desc:     "environment variable",
+			args:     []string{"--repo-name", "traefik"},
+			env:      "env.yaml",
+			expected: "env.yaml",
+		},
+		{
+			desc:     "after terminator",
+			args:     []string{"--", "--config", "ci.yaml"},
+			env:      "env.yaml",
+			expected: "env.yaml",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Setenv("ASSIMILIS_CONFIG", test.env)
+
+			require.Equal(t, test.expected, configFilePath(test.args))
+		})
+	}
+}
+
+func TestEnvVarName(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, "ASSIMILIS_REPO_NAME", envVarName("repo-name"))
+	require.Equal(t, "ASSIMILIS_PYTHON_SITE_PACKAGES_DIR", envVarName("python-site-packages-dir"))
+}
+
+func TestBuildFlags_Precedence(t *testing.T) {
+	t.Setenv("ASSIMILIS_SPDX_VERSION", "v3.26.0")
+	t.Setenv("ASSIMILIS_REPO_NAME", "from-env")
+
+	// Values loaded from the config file are the defaults of the flags.
+	cfg := generator.DefaultConfig()
+	cfg.RepoName = "from-file"
+	cfg.HTMLFileName = "licenses.html"
+	cfg.Filters = &generator.Filters{}
+
+	cmd := &cli.Command{
+		Name:   "assimilis",
+		Flags:  buildFlags(&cfg),
+		Action: func(context.Context, *cli.Command) error { return nil },
+	}
+
+	require.NoError(t, cmd.Run(t.Context(), []string{"assimilis", "--repo-name", "from-flag", "--filters", "filters.json"}))
+
+	require.Equal(t, "from-flag", cfg.RepoName)
+	require.Equal(t, "v3.26.0", cfg.SPDXVersion)
+	require.Equal(t, "licenses.html", cfg.HTMLFileName)
+	require.Equal(t, "filters.json", cfg.FiltersPath)
+	require.Nil(t, cfg.Filters)
+}
+
+func TestBuildFlags_Outputs(t *testing.T) {
+	t.Parallel()
+
+	cfg := generator.DefaultConfig()
+	cfg.Outputs = []generator.Output{{Template: "json", Path: "from-file.json"}}
+
+	cmd := &cli.Command{
+		Name:   "assimilis",
+		Flags:  buildFlags(&cfg),
+		Action: func(context.Context, *cli.Command) error { return nil },
+	}
+
+	require.NoError(t, cmd.Run(t.Context(), []string{"assimilis", "--output", "html=licenses.html", "--output", "docs.md.gotpl=../docs/licenses.md"}))
+
+	require.Equal(t, []generator.Output{
+		{Template: "html", Path: "licenses.html"},
+		{Template: "docs.md.gotpl", Path: "../docs/licenses.md"},
+	}, cfg.Outputs)
+
+	require.Error(t, cmd.Run(t.Context(), []string{"assimilis", "--output", "licenses.html"}))
+}
+
+func TestPrintConfig(t *testing.T) {
+	t.Parallel()
+
+	cfg := generator.DefaultConfig()
+	cfg.RepoName = "traefik"
+
+	var buf bytes.Buffer
+	require.NoError(t, printConfig(&buf, cfg))
+
+	require.Contains(t, buf.String(), "repo-name: traefik\n")
+	require.Contains(t, buf.String(), "spdx-version: "+cfg.SPDXVersion+"\n")
+}
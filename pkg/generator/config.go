@@ -1,28 +1,95 @@
 // Package generator generates NOTICE/HTML attribution artifacts from a CycloneDX SBOM.
 package generator
 
-// Config the global configuration.
+// Config the global configuration. The yaml keys are the names of the CLI
+// flags.
 type Config struct {
-	RepoName string
+	RepoName string `yaml:"repo-name"`
 
-	SBOMPath         string
-	HTMLTemplatePath string
-	NoticeTplPath    string
+	SBOMPath         string `yaml:"sbom-dir"`
+	HTMLTemplatePath string `yaml:"html-template"`
+	NoticeTplPath    string `yaml:"notice-template"`
+	// TemplateDir holds partial templates, parsed along with the HTML and
+	// notice templates.
+	TemplateDir string `yaml:"template-dir"`
 
-	OutDir         string
-	OutLicensesDir string
+	OutDir         string `yaml:"output-dir"`
+	OutLicensesDir string `yaml:"licenses-dir"`
 
-	HTMLFileName   string
-	NoticeFileName string
+	HTMLFileName   string `yaml:"html-filename"`
+	NoticeFileName string `yaml:"notice-filename"`
 
-	LicenseMapPath         string
-	LicenseCorrectionsPath string
-	FiltersPath            string
+	// Outputs lists the files to render. When empty, the HTML and NOTICE files
+	// are rendered from HTMLTemplatePath and NoticeTplPath to HTMLFileName and
+	// NoticeFileName.
+	Outputs []Output `yaml:"outputs,omitempty"`
 
-	NodeModulesDir        string
-	PythonSitePackagesDir string
+	// OrgDataDir holds the organization-wide data files (license-map.json,
+	// license-corrections.json, filters.json, curations.json), merged over the
+	// embedded ones.
+	OrgDataDir string `yaml:"org-data-dir"`
 
-	SPDXVersion string
+	// The repository data files are given by path, or inline in the
+	// configuration file, and merged over the organization-wide ones. Inline
+	// data takes precedence.
+	LicenseMapPath         string                        `yaml:"-"`
+	LicenseCorrectionsPath string                        `yaml:"-"`
+	FiltersPath            string                        `yaml:"-"`
+	CurationsPath          string                        `yaml:"-"`
+	ExtraComponentsPath    string                        `yaml:"-"`
+	LicenseMap             map[string]string             `yaml:"-"`
+	LicenseCorrections     map[string]LicenseCorrections `yaml:"-"`
+	Filters                *Filters                      `yaml:"-"`
+	Curations              map[string]Curation           `yaml:"-"`
+	ExtraComponents        []ExtraComponent              `yaml:"-"`
+
+	NodeModulesDir         string   `yaml:"node-modules-dir"`
+	PythonSitePackagesDirs []string `yaml:"python-site-packages-dir"`
+	CargoVendorDir         string   `yaml:"cargo-vendor-dir"`
+	MavenRepositoryDir     string   `yaml:"maven-repository-dir"`
+	ComposerVendorDir      string   `yaml:"composer-vendor-dir"`
+	GemHome                string   `yaml:"gem-home"`
+	RootFS                 string   `yaml:"rootfs"`
+
+	DetectLicenses            bool    `yaml:"detect-licenses"`
+	LicenseDetectionThreshold float64 `yaml:"license-detection-threshold"`
+
+	// ComponentLicenseTexts uses the license texts shipped by the components
+	// instead of the SPDX reference texts.
+	ComponentLicenseTexts bool `yaml:"component-license-texts"`
+
+	// PreferDeclaredLicenses uses the licenses declared by the component authors
+	// instead of the ones concluded by analysis when the SBOM has both.
+	PreferDeclaredLicenses bool `yaml:"prefer-declared-licenses"`
+
+	// FirstPartyPrefixes lists the PURL or package name prefixes of the
+	// components owned by the organization, which are not attributed.
+	FirstPartyPrefixes []string `yaml:"first-party-prefix"`
+	// FirstPartyPackageJSONs lists the package.json files whose npm scope, or
+	// name when unscoped, identifies the project's own npm packages.
+	FirstPartyPackageJSONs []string `yaml:"first-party-package-json"`
+	// FirstPartyNamespaces extends the first-party detection to the components
+	// sharing the namespace (npm scope, Maven group, etc.) or the supplier of
+	// the SBOM subject, which third-party components can share too.
+	FirstPartyNamespaces bool `yaml:"first-party-namespaces"`
+	// DisableFirstPartyDetection disables the detection of the project's own
+	// components from the SBOM metadata, go.mod and package.json.
+	DisableFirstPartyDetection bool `yaml:"disable-first-party-detection"`
+
+	SPDXVersion string `yaml:"spdx-version"`
+}
+
+// Output describes a file rendered from the model.
+type Output struct {
+	// Template is the name of a built-in template, such as "html", "notice" or
+	// "json", or the path to a template file.
+	Template string `yaml:"template"`
+	// Path is the path of the output file, relative to the output directory.
+	Path string `yaml:"path"`
+	// Engine is "html" (html/template, with contextual escaping) or "text"
+	// (text/template). When empty, it is the engine of the built-in template,
+	// or "html" for a Path ending with ".html" or ".htm" and "text" otherwise.
+	Engine string `yaml:"engine,omitempty"`
 }
 
 // DefaultConfig returns the default configuration.
@@ -40,6 +107,10 @@ func DefaultConfig() Config {
 		HTMLFileName:   defaultHTMLFileName,
 		NoticeFileName: defaultNoticeFileName,
 
+		LicenseDetectionThreshold: 0.9,
+
+		FirstPartyPackageJSONs: []string{"package.json"},
+
 		SPDXVersion: "v3.27.0",
 	}
 }
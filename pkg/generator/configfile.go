@@ -0,0 +1,260 @@
+package generator
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+)
+
+// configFileNames lists the names of the configuration file looked up at the
+// repository root.
+var configFileNames = []string{"assimilis.yaml", "assimilis.yml"}
+
+// FindConfigFile returns the configuration file of the repository: it is looked
+// up in the working directory, then in its parents up to the repository root
+// (the first directory containing .git). It returns an empty string when there
+// is none.
+func FindConfigFile() string {
+	dir, err := os.Getwd()
+	if err != nil {
+		return ""
+	}
+
+	for {
+		for _, name := range configFileNames {
+			if path := filepath.Join(dir, name); isFile(path) {
+				return path
+			}
+		}
+
+		if _, err := os.Stat(filepath.Join(dir, ".git")); err == nil {
+			return ""
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ""
+		}
+
+		dir = parent
+	}
+}
+
+// LoadConfigFile applies the configuration file at path to cfg. Settings absent
+// from the file are left untouched. Relative paths are resolved against the
+// directory of the file. Unknown settings are rejected.
+func LoadConfigFile(path string, cfg *Config) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	var keys map[string]yaml.Node
+	if err := yaml.Unmarshal(data, &keys); err != nil {
+		return fmt.Errorf("failed to parse config file %q: %w", path, err)
+	}
+
+	// Unknown keys are rejected so that a misspelled setting is not ignored.
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+
+	if err := dec.Decode(&configFileKeys{}); err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("failed to parse config file %q: %w", path, err)
+	}
+
+	if err := yaml.Unmarshal(data, cfg); err != nil {
+		return fmt.Errorf("failed to parse config file %q: %w", path, err)
+	}
+
+	dir := filepath.Dir(path)
+
+	for key, p := range cfg.pathSettings() {
+		if _, ok := keys[key]; ok {
+			*p = resolvePath(dir, *p)
+		}
+	}
+
+	if _, ok := keys["python-site-packages-dir"]; ok {
+		for i, p := range cfg.PythonSitePackagesDirs {
+			cfg.PythonSitePackagesDirs[i] = resolvePath(dir, p)
+		}
+	}
+
+	if _, ok := keys["first-party-package-json"]; ok {
+		for i, p := range cfg.FirstPartyPackageJSONs {
+			cfg.FirstPartyPackageJSONs[i] = resolvePath(dir, p)
+		}
+	}
+
+	// Output templates are relative to the file, and output paths to the output
+	// directory.
+	for i, o := range cfg.Outputs {
+		if !isBuiltin(o.Template) {
+			cfg.Outputs[i].Template = resolvePath(dir, o.Template)
+		}
+	}
+
+	// The license files of inline extra components are relative to the file.
+	for i, e := range cfg.ExtraComponents {
+		cfg.ExtraComponents[i].LicenseFile = resolvePath(dir, e.LicenseFile)
+	}
+
+	// Like the --output-dir flag, output-dir moves the directories under it
+	// unless they are set.
+	if _, ok := keys["output-dir"]; ok {
+		if _, ok := keys["licenses-dir"]; !ok {
+			cfg.OutLicensesDir = filepath.Join(cfg.OutDir, "licenses")
+		}
+
+		if _, ok := keys["sbom-dir"]; !ok {
+			cfg.SBOMPath = filepath.Join(cfg.OutDir, "sbom")
+		}
+	}
+
+	return nil
+}
+
+// pathSettings returns the settings holding a path, by configuration key.
+func (c *Config) pathSettings() map[string]*string {
+	return map[string]*string{
+		"sbom-dir":             &c.SBOMPath,
+		"html-template":        &c.HTMLTemplatePath,
+		"notice-template":      &c.NoticeTplPath,
+		"template-dir":         &c.TemplateDir,
+		"output-dir":           &c.OutDir,
+		"licenses-dir":         &c.OutLicensesDir,
+		"org-data-dir":         &c.OrgDataDir,
+		"license-map":          &c.LicenseMapPath,
+		"license-corrections":  &c.LicenseCorrectionsPath,
+		"filters":              &c.FiltersPath,
+		"curations":            &c.CurationsPath,
+		"extra-components":     &c.ExtraComponentsPath,
+		"node-modules-dir":     &c.NodeModulesDir,
+		"cargo-vendor-dir":     &c.CargoVendorDir,
+		"maven-repository-dir": &c.MavenRepositoryDir,
+		"composer-vendor-dir":  &c.ComposerVendorDir,
+		"gem-home":             &c.GemHome,
+		"rootfs":               &c.RootFS,
+	}
+}
+
+func resolvePath(dir, path string) string {
+	if path == "" || filepath.IsAbs(path) {
+		return path
+	}
+
+	return filepath.Join(dir, path)
+}
+
+// dataSettings holds the data files of the configuration, each given by path
+// (a string) or inline (a mapping).
+type dataSettings struct {
+	LicenseMap         yaml.Node `yaml:"license-map"`
+	LicenseCorrections yaml.Node `yaml:"license-corrections"`
+	Filters            yaml.Node `yaml:"filters"`
+	Curations          yaml.Node `yaml:"curations"`
+	ExtraComponents    yaml.Node `yaml:"extra-components"`
+}
+
+// configFileKeys has the keys accepted by Config.UnmarshalYAML. It checks them
+// for unknown ones, which the decoding of a yaml.Node does not report.
+type configFileKeys struct {
+	plainConfig  `yaml:",inline"`
+	dataSettings `yaml:",inline"`
+}
+
+type plainConfig Config
+
+// UnmarshalYAML implements yaml.Unmarshaler.
+func (c *Config) UnmarshalYAML(node *yaml.Node) error {
+	type plain Config
+	if err := node.Decode((*plain)(c)); err != nil {
+		return err
+	}
+
+	var data dataSettings
+	if err := node.Decode(&data); err != nil {
+		return err
+	}
+
+	if err := decodeDataSetting(&data.LicenseMap, &c.LicenseMapPath, &c.LicenseMap); err != nil {
+		return fmt.Errorf("license-map: %w", err)
+	}
+
+	if err := decodeDataSetting(&data.LicenseCorrections, &c.LicenseCorrectionsPath, &c.LicenseCorrections); err != nil {
+		return fmt.Errorf("license-corrections: %w", err)
+	}
+
+	if err := decodeDataSetting(&data.Filters, &c.FiltersPath, &c.Filters); err != nil {
+		return fmt.Errorf("filters: %w", err)
+	}
+
+	if err := decodeDataSetting(&data.Curations, &c.CurationsPath, &c.Curations); err != nil {
+		return fmt.Errorf("curations: %w", err)
+	}
+
+	if err := decodeDataSetting(&data.ExtraComponents, &c.ExtraComponentsPath, &c.ExtraComponents); err != nil {
+		return fmt.Errorf("extra-components: %w", err)
+	}
+
+	return nil
+}
+
+func decodeDataSetting[T any](node *yaml.Node, path *string, inline *T) error {
+	switch node.Kind {
+	case 0:
+		return nil
+	case yaml.ScalarNode:
+		return node.Decode(path)
+	default:
+		return node.Decode(inline)
+	}
+}
+
+// MarshalYAML implements yaml.Marshaler.
+func (c Config) MarshalYAML() (any, error) {
+	type plain Config
+
+	var node yaml.Node
+	if err := node.Encode(plain(c)); err != nil {
+		return nil, err
+	}
+
+	settings := []struct {
+		key    string
+		path   string
+		inline any
+		isSet  bool
+	}{
+		{key: "license-map", path: c.LicenseMapPath, inline: c.LicenseMap, isSet: c.LicenseMap != nil},
+		{key: "license-corrections", path: c.LicenseCorrectionsPath, inline: c.LicenseCorrections, isSet: c.LicenseCorrections != nil},
+		{key: "filters", path: c.FiltersPath, inline: c.Filters, isSet: c.Filters != nil},
+		{key: "curations", path: c.CurationsPath, inline: c.Curations, isSet: c.Curations != nil},
+		{key: "extra-components", path: c.ExtraComponentsPath, inline: c.ExtraComponents, isSet: c.ExtraComponents != nil},
+	}
+
+	for _, s := range settings {
+		value := s.inline
+		if !s.isSet {
+			if s.path == "" {
+				continue
+			}
+
+			value = s.path
+		}
+
+		var v yaml.Node
+		if err := v.Encode(value); err != nil {
+			return nil, fmt.Errorf("%s: %w", s.key, err)
+		}
+
+		node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: s.key}, &v)
+	}
+
+	return &node, nil
+}
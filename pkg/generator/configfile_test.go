@@ -0,0 +1,135 @@
+package generator
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func TestLoadConfigFile(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "assimilis.yaml")
+
+	content := `repo-name: traefik
+output-dir: out
+html-template: /templates/tpl.gotpl
+python-site-packages-dir: [venv/site-packages]
+first-party-package-json: [package.json, webui/package.json]
+detect-licenses: true
+license-map:
+  Apache 2: Apache-2.0
+license-corrections: corrections.json
+filters:
+  scopes: [excluded]
+  properties:
+    - name: cdx:npm:package:development
+      value: ^true$
+  include:
+    purlRegex: ['^pkg:npm/typescript@']
+outputs:
+  - template: html
+    path: licenses.html
+  - template: templates/docs.md.gotpl
+    path: ../docs/licenses.md
+  - template: spdx
+    path: traefik.spdx.json
+extra-components:
+  - name: font
+    license: OFL-1.1
+    licenseFile: licenses/font.txt
+`
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+
+	cfg := DefaultConfig()
+	require.NoError(t, LoadConfigFile(path, &cfg))
+
+	assert.Equal(t, "traefik", cfg.RepoName)
+	assert.Equal(t, filepath.Join(dir, "out"), cfg.OutDir)
+	assert.Equal(t, filepath.Join(dir, "out", "licenses"), cfg.OutLicensesDir)
+	assert.Equal(t, filepath.Join(dir, "out", "sbom"), cfg.SBOMPath)
+	assert.Equal(t, "/templates/tpl.gotpl", cfg.HTMLTemplatePath)
+	assert.Equal(t, []string{filepath.Join(dir, "venv", "site-packages")}, cfg.PythonSitePackagesDirs)
+	assert.Equal(t, []string{filepath.Join(dir, "package.json"), filepath.Join(dir, "webui", "package.json")}, cfg.FirstPartyPackageJSONs)
+	assert.True(t, cfg.DetectLicenses)
+	assert.InDelta(t, 0.9, cfg.LicenseDetectionThreshold, 0)
+	assert.Equal(t, defaultHTMLFileName, cfg.HTMLFileName)
+
+	assert.Equal(t, map[string]string{"Apache 2": "Apache-2.0"}, cfg.LicenseMap)
+	assert.Empty(t, cfg.LicenseMapPath)
+	assert.Nil(t, cfg.LicenseCorrections)
+	assert.Equal(t, filepath.Join(dir, "corrections.json"), cfg.LicenseCorrectionsPath)
+
+	require.NotNil(t, cfg.Filters)
+	assert.Equal(t, []string{"excluded"}, cfg.Filters.Scopes)
+	require.Len(t, cfg.Filters.Properties, 1)
+	assert.True(t, cfg.Filters.Properties[0].Value.MatchString("true"))
+	require.Len(t, cfg.Filters.Include.PURLRegex, 1)
+	assert.True(t, cfg.Filters.Include.PURLRegex[0].MatchString("pkg:npm/typescript@5.0.0"))
+
+	assert.Equal(t, []Output{
+		{Template: "html", Path: "licenses.html"},
+		{Template: filepath.Join(dir, "templates", "docs.md.gotpl"), Path: "../docs/licenses.md"},
+		{Template: "spdx", Path: "traefik.spdx.json"},
+	}, cfg.Outputs)
+
+	assert.Equal(t, []ExtraComponent{{Name: "font", License: "OFL-1.1", LicenseFile: filepath.Join(dir, "licenses", "font.txt")}}, cfg.ExtraComponents)
+}
+
+func TestLoadConfigFile_Invalid(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "assimilis.yaml")
+
+	require.NoError(t, os.WriteFile(path, []byte("filters:\n  purlRegex: ['(']\n"), 0o644))
+
+	cfg := DefaultConfig()
+	require.Error(t, LoadConfigFile(path, &cfg))
+	require.Error(t, LoadConfigFile(filepath.Join(dir, "missing.yaml"), &cfg))
+}
+
+func TestLoadConfigFile_UnknownKey(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "assimilis.yaml")
+
+	require.NoError(t, os.WriteFile(path, []byte("licence_map:\n  Apache 2: Apache-2.0\n"), 0o644))
+
+	cfg := DefaultConfig()
+	err := LoadConfigFile(path, &cfg)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), path)
+	assert.Contains(t, err.Error(), "licence_map")
+
+	// An empty file is valid.
+	require.NoError(t, os.WriteFile(path, nil, 0o644))
+	require.NoError(t, LoadConfigFile(path, &cfg))
+}
+
+func TestConfig_MarshalYAML(t *testing.T) {
+	t.Parallel()
+
+	cfg := DefaultConfig()
+	cfg.RepoName = "traefik"
+	cfg.FiltersPath = "filters.json"
+	cfg.LicenseCorrections = map[string]LicenseCorrections{"pkg:npm/foo": {{License: "MIT", Justification: "LICENSE file."}}}
+
+	out, err := yaml.Marshal(cfg)
+	require.NoError(t, err)
+
+	var back Config
+	require.NoError(t, yaml.Unmarshal(out, &back))
+
+	assert.Equal(t, "traefik", back.RepoName)
+	assert.Equal(t, "filters.json", back.FiltersPath)
+	assert.Nil(t, back.Filters)
+	assert.Equal(t, cfg.LicenseCorrections, back.LicenseCorrections)
+	assert.NotContains(t, string(out), "license-map")
+}
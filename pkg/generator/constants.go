@@ -7,6 +7,7 @@ const (
 	embeddedLicenseMapPath         = "data/license-map.json"
 	embeddedLicenseCorrectionsPath = "data/license-corrections.json"
 	embeddedFiltersPath            = "data/filters.json"
+	embeddedCurationsPath          = "data/curations.json"
 
 	spdxNameMapURLFmt     = "https://raw.githubusercontent.com/spdx/license-list-data/%s/json/licenses.json"
 	spdxLicenseTextURLFmt = "https://raw.githubusercontent.com/spdx/license-list-data/%s/text/%s.txt"
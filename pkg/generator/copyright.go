@@ -1,45 +1,177 @@
 package generator
 
 import (
+	"bytes"
 	"encoding/json"
-	"net/url"
+	"net/mail"
+	"net/textproto"
 	"os"
 	"path/filepath"
+	"regexp"
 	"strings"
 	"unicode"
+
+	"github.com/rs/zerolog/log"
 )
 
-// copyrightEnricher resolves copyright notices from local filesystem caches
-// for Go, npm, and Python packages.
+// licenseFile is a license file shipped with a package.
+type licenseFile struct {
+	// Name identifies the file in logs (e.g. "LICENSE-MIT").
+	Name string
+	Text string
+}
+
+// notice is a copyright statement along with where it was found, so reviewers
+// can judge how much to trust it.
+type notice struct {
+	Text   string
+	Source string
+}
+
+// copyrightEnricher resolves copyright notices, license files and declared
+// licenses from local filesystem caches for Go, npm, Python, Cargo, Maven,
+// Composer and RubyGems packages, and from a root filesystem for distro (deb, apk, rpm) packages.
 type copyrightEnricher struct {
 	gomodcache         string
 	nodeModulesDir     string
-	pythonSitePackages string
+	pythonSitePackages []string
+	cargoRegistrySrc   string
+	cargoVendorDir     string
+	mavenRepository    mavenRepository
+	composerVendorDir  string
+	gemHome            string
+	rootFS             rootFS
 }
 
 func newCopyrightEnricher(cfg Config) copyrightEnricher {
 	return copyrightEnricher{
 		gomodcache:         goModCache(),
 		nodeModulesDir:     resolveNodeModulesDir(cfg.NodeModulesDir),
-		pythonSitePackages: cfg.PythonSitePackagesDir,
+		pythonSitePackages: resolvePythonSitePackagesDirs(cfg.PythonSitePackagesDirs),
+		cargoRegistrySrc:   cargoRegistrySrc(),
+		cargoVendorDir:     resolveCargoVendorDir(cfg.CargoVendorDir),
+		mavenRepository:    resolveMavenRepository(cfg.MavenRepositoryDir),
+		composerVendorDir:  resolveComposerVendorDir(cfg.ComposerVendorDir),
+		gemHome:            resolveGemHome(cfg.GemHome),
+		rootFS:             loadRootFS(cfg.RootFS),
 	}
 }
 
-func (e copyrightEnricher) enrich(purl, existing string) string {
+func (e copyrightEnricher) enrich(purl, existing string) notice {
 	if existing != "" {
-		return existing
+		return notice{Text: existing, Source: "sbom"}
 	}
 
-	switch {
-	case strings.HasPrefix(purl, "pkg:golang/"):
+	p, _ := parsePURL(purl)
+
+	switch p.Type {
+	case "golang":
 		return extractGoCopyrightFromCache(e.gomodcache, purl)
-	case strings.HasPrefix(purl, "pkg:npm/"):
+	case "npm":
 		return extractNpmCopyright(e.nodeModulesDir, purl)
-	case strings.HasPrefix(purl, "pkg:pypi/"):
+	case "pypi":
 		return extractPythonCopyright(e.pythonSitePackages, purl)
+	case "cargo":
+		return extractCargoCopyright(e.cargoRegistrySrc, e.cargoVendorDir, purl)
+	case "maven":
+		return extractMavenCopyright(e.mavenRepository, purl)
+	case "composer":
+		return extractComposerCopyright(e.composerVendorDir, purl)
+	case "gem":
+		return extractGemCopyright(e.gemHome, purl)
+	case "deb", "apk", "rpm":
+		return extractOSPackageCopyright(e.rootFS, purl)
+	}
+
+	return notice{}
+}
+
+// licenseFiles returns the license files shipped with the package, looked up in
+// the same local caches as copyright notices.
+func (e copyrightEnricher) licenseFiles(purl string) []licenseFile {
+	p, _ := parsePURL(purl)
+
+	switch p.Type {
+	case "golang":
+		return readLicenseFiles(findCaseInsensitiveFiles(goModuleDir(e.gomodcache, purl), licenseFileNames))
+	case "npm":
+		return readLicenseFiles(findCaseInsensitiveFiles(npmPackageDir(e.nodeModulesDir, purl), licenseFileNames))
+	case "pypi":
+		return readLicenseFiles(pythonLicenseFilePaths(pythonDistInfoFromPURL(e.pythonSitePackages, purl)))
+	case "cargo":
+		return readLicenseFiles(cargoLicenseFilePaths(cargoCrateDir(e.cargoRegistrySrc, e.cargoVendorDir, purl)))
+	case "maven":
+		return mavenLicenseFiles(e.mavenRepository, purl)
+	case "composer":
+		return readLicenseFiles(composerLicenseFilePaths(e.composerVendorDir, purl))
+	case "gem":
+		return readLicenseFiles(gemLicenseFilePaths(e.gemHome, purl))
+	case "deb", "apk", "rpm":
+		return readLicenseFiles(e.rootFS.licenseFilePaths(purl))
+	}
+
+	return nil
+}
+
+// noticeFiles returns the NOTICE files shipped with the package, which the
+// Apache License requires redistributing, looked up in the same local caches as
+// license files.
+func (e copyrightEnricher) noticeFiles(purl string) []licenseFile {
+	p, _ := parsePURL(purl)
+
+	var dir string
+
+	switch p.Type {
+	case "golang":
+		dir = goModuleDir(e.gomodcache, purl)
+	case "npm":
+		dir = npmPackageDir(e.nodeModulesDir, purl)
+	case "cargo":
+		dir = cargoCrateDir(e.cargoRegistrySrc, e.cargoVendorDir, purl)
+	case "maven":
+		return mavenNoticeFiles(e.mavenRepository, purl)
+	case "composer":
+		dir, _ = composerPackageDir(e.composerVendorDir, purl)
+	case "gem":
+		if fullName := gemFullName(e.gemHome, purl); fullName != "" {
+			dir = filepath.Join(e.gemHome, "gems", fullName)
+		}
 	}
 
-	return ""
+	if dir == "" {
+		return nil
+	}
+
+	return readLicenseFiles(findCaseInsensitiveFiles(dir, noticeFileNames))
+}
+
+// declaredLicenses returns the license expressions declared in the package
+// metadata, used when the SBOM reports no license.
+func (e copyrightEnricher) declaredLicenses(purl string) []string {
+	p, _ := parsePURL(purl)
+
+	switch p.Type {
+	case "cargo":
+		if expr := cargoDeclaredLicense(e.cargoRegistrySrc, e.cargoVendorDir, purl); expr != "" {
+			return []string{expr}
+		}
+	case "maven":
+		return mavenDeclaredLicenses(e.mavenRepository, purl)
+	case "composer":
+		if expr := composerDeclaredLicense(e.composerVendorDir, purl); expr != "" {
+			return []string{expr}
+		}
+	case "gem":
+		if expr := gemDeclaredLicense(e.gemHome, purl); expr != "" {
+			return []string{expr}
+		}
+	case "deb", "apk", "rpm":
+		if expr := osPackageDeclaredLicense(e.rootFS, purl); expr != "" {
+			return []string{expr}
+		}
+	}
+
+	return nil
 }
 
 // ─── Go ──────────────────────────────────────────────────────────────────────
@@ -61,29 +193,24 @@ func goModCache() string {
 
 // extractGoCopyrightFromCache looks up the LICENSE file in the Go module cache
 // for the given PURL and returns the first copyright line found.
-func extractGoCopyrightFromCache(gomodcache, purl string) string {
-	if idx := strings.Index(purl, "?"); idx != -1 {
-		purl = purl[:idx]
+func extractGoCopyrightFromCache(gomodcache, purl string) notice {
+	modDir := goModuleDir(gomodcache, purl)
+	if modDir == "" {
+		return notice{}
 	}
 
-	rest, ok := strings.CutPrefix(purl, "pkg:golang/")
-	if !ok {
-		return ""
-	}
-
-	idx := strings.LastIndex(rest, "@")
-	if idx == -1 {
-		return ""
-	}
+	return fileNotice(filepath.Join(modDir, "LICENSE"))
+}
 
-	modulePath, version := rest[:idx], rest[idx+1:]
-	if modulePath == "" || version == "" {
+// goModuleDir returns the module cache directory of the module identified by
+// the given PURL, or an empty string if the PURL is not a versioned Go module.
+func goModuleDir(gomodcache, purl string) string {
+	p, ok := parsePURL(purl)
+	if !ok || p.Type != "golang" || p.Version == "" {
 		return ""
 	}
 
-	licensePath := filepath.Join(gomodcache, escapeModulePath(modulePath)+"@"+version, "LICENSE")
-
-	return firstCopyrightLine(readFileText(licensePath))
+	return filepath.Join(gomodcache, escapeModulePath(p.fullName())+"@"+p.Version)
 }
 
 // escapeModulePath escapes a Go module path for the module cache filesystem
@@ -122,9 +249,26 @@ func resolveNodeModulesDir(configured string) string {
 }
 
 // extractNpmCopyright reads the copyright notice for an npm package from the
-// node_modules directory. It tries LICENSE files first, then falls back to the
-// author field in package.json.
-func extractNpmCopyright(nodeModulesDir, purl string) string {
+// node_modules directory. Sources are tried in order: license files, then the
+// author, contributors and maintainers fields of package.json.
+func extractNpmCopyright(nodeModulesDir, purl string) notice {
+	pkgDir := npmPackageDir(nodeModulesDir, purl)
+	if pkgDir == "" {
+		return notice{}
+	}
+
+	for _, filename := range findCaseInsensitiveFiles(pkgDir, licenseFileNames) {
+		if n := fileNotice(filename); n.Text != "" {
+			return n
+		}
+	}
+
+	return npmPackageJSONCopyright(filepath.Join(pkgDir, "package.json"))
+}
+
+// npmPackageDir returns the node_modules directory of the package identified
+// by the given PURL.
+func npmPackageDir(nodeModulesDir, purl string) string {
 	if nodeModulesDir == "" {
 		return ""
 	}
@@ -134,153 +278,493 @@ func extractNpmCopyright(nodeModulesDir, purl string) string {
 		return ""
 	}
 
-	pkgDir := filepath.Join(nodeModulesDir, filepath.FromSlash(name))
+	return filepath.Join(nodeModulesDir, filepath.FromSlash(name))
+}
 
-	//nolint:misspell // support British spelling
-	licenseFileNames := []string{"LICENSE", "LICENSE.md", "LICENSE.txt", "LICENCE", "LICENCE.md"}
+// parseNpmPURL returns the package name, including its scope (e.g.
+// "@babel/core"), and the version of an npm PURL.
+func parseNpmPURL(purl string) (string, string) {
+	p, ok := parsePURL(purl)
+	if !ok || p.Type != "npm" || p.Version == "" {
+		return "", ""
+	}
+
+	return p.fullName(), p.Version
+}
 
-	if filename := findCaseInsensitiveFile(pkgDir, licenseFileNames); filename != "" {
-		if c := firstCopyrightLine(readFileText(filename)); c != "" {
-			return c
+// npmPackageJSONCopyright builds a notice from the people listed in
+// package.json: author first, then contributors, then maintainers.
+func npmPackageJSONCopyright(path string) notice {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return notice{}
+	}
+
+	var pkg struct {
+		Author       json.RawMessage `json:"author"`
+		Contributors json.RawMessage `json:"contributors"`
+		Maintainers  json.RawMessage `json:"maintainers"`
+	}
+	if err := json.Unmarshal(data, &pkg); err != nil {
+		return notice{}
+	}
+
+	fields := []struct {
+		name string
+		raw  json.RawMessage
+	}{
+		{name: "author", raw: pkg.Author},
+		{name: "contributors", raw: pkg.Contributors},
+		{name: "maintainers", raw: pkg.Maintainers},
+	}
+
+	for _, field := range fields {
+		if names := npmPeople(field.raw); len(names) > 0 {
+			return notice{
+				Text:   "Copyright (c) " + strings.Join(names, ", "),
+				Source: "package.json " + field.name,
+			}
 		}
 	}
 
-	return npmAuthorCopyright(filepath.Join(pkgDir, "package.json"))
+	return notice{}
 }
 
-func parseNpmPURL(purl string) (string, string) {
-	if idx := strings.Index(purl, "?"); idx != -1 {
-		purl = purl[:idx]
+// npmPeople returns the names found in a package.json people field. The field
+// may hold a single person or a list of them, each either in string form
+// ("Name <email> (url)") or object form ({"name": "Name", ...}).
+func npmPeople(raw json.RawMessage) []string {
+	if len(raw) == 0 {
+		return nil
 	}
 
-	rest, ok := strings.CutPrefix(purl, "pkg:npm/")
-	if !ok {
-		return "", ""
+	var list []json.RawMessage
+	if err := json.Unmarshal(raw, &list); err != nil {
+		list = []json.RawMessage{raw}
 	}
 
-	// Use LastIndex so the "@" in scoped package names (e.g. "@babel/core") is
-	// not mistaken for the version separator.
-	idx := strings.LastIndex(rest, "@")
-	if idx == -1 {
-		return "", ""
+	var names []string
+
+	for _, item := range list {
+		var s string
+		if err := json.Unmarshal(item, &s); err == nil {
+			if name := personName(s); name != "" {
+				names = append(names, name)
+			}
+
+			continue
+		}
+
+		var obj struct {
+			Name string `json:"name"`
+		}
+		if err := json.Unmarshal(item, &obj); err == nil && strings.TrimSpace(obj.Name) != "" {
+			names = append(names, strings.TrimSpace(obj.Name))
+		}
 	}
 
-	// URL-decode for scoped packages encoded as %40babel%2Fcore.
-	decoded, err := url.PathUnescape(rest[:idx])
-	if err != nil {
-		decoded = rest[:idx]
+	return names
+}
+
+// ─── Python ──────────────────────────────────────────────────────────────────
+
+// resolvePythonSitePackagesDirs returns the site-packages directories to use.
+// If configured is empty, it probes $VIRTUAL_ENV and the usual ".venv" and
+// "venv" virtual environments.
+func resolvePythonSitePackagesDirs(configured []string) []string {
+	if len(configured) > 0 {
+		return configured
 	}
 
-	return decoded, rest[idx+1:]
+	var roots []string
+	if v := os.Getenv("VIRTUAL_ENV"); v != "" {
+		roots = append(roots, v)
+	}
+
+	return pythonSitePackagesDirs(append(roots, ".venv", "venv"))
 }
 
-func npmAuthorCopyright(path string) string {
-	data, err := os.ReadFile(path)
-	if err != nil {
-		return ""
+// pythonSitePackagesDirs returns every site-packages directory found under the
+// given virtual environment roots: all "lib/python3.*/site-packages" on POSIX,
+// and "Lib/site-packages" on Windows.
+func pythonSitePackagesDirs(roots []string) []string {
+	seen := map[string]struct{}{}
+
+	var dirs []string
+
+	for _, root := range roots {
+		matches, _ := filepath.Glob(filepath.Join(root, "lib", "python3.*", "site-packages"))
+		matches = append(matches, filepath.Join(root, "Lib", "site-packages"))
+
+		for _, dir := range matches {
+			dir = filepath.Clean(dir)
+			if _, ok := seen[dir]; ok || !isDir(dir) {
+				continue
+			}
+
+			seen[dir] = struct{}{}
+			dirs = append(dirs, dir)
+		}
 	}
 
-	var pkg struct {
-		Author json.RawMessage `json:"author"`
+	return dirs
+}
+
+// extractPythonCopyright reads the copyright notice for a PyPI package from its
+// dist-info directory. Copyright lines from the declared License-File entries
+// are preferred; the Author, Author-email, Maintainer and Maintainer-email
+// fields of METADATA are used as fallbacks, in that order.
+func extractPythonCopyright(sitePackagesDirs []string, purl string) notice {
+	distInfo := pythonDistInfoFromPURL(sitePackagesDirs, purl)
+	if distInfo == "" {
+		return notice{}
 	}
-	if err := json.Unmarshal(data, &pkg); err != nil || pkg.Author == nil {
+
+	return pythonDistInfoCopyright(distInfo)
+}
+
+// pythonDistInfoFromPURL returns the dist-info directory of the package
+// identified by the given PURL.
+func pythonDistInfoFromPURL(sitePackagesDirs []string, purl string) string {
+	if len(sitePackagesDirs) == 0 {
 		return ""
 	}
 
-	// String form: "Name <email> (url)"
-	var s string
-	if err := json.Unmarshal(pkg.Author, &s); err == nil {
-		return formatAuthorCopyright(s)
+	p, ok := parsePURL(purl)
+	if !ok || p.Type != "pypi" || p.Version == "" {
+		return ""
 	}
 
-	// Object form: {"name": "Name", ...}
-	var obj struct {
-		Name string `json:"name"`
-	}
-	if err := json.Unmarshal(pkg.Author, &obj); err == nil && obj.Name != "" {
-		return "Copyright (c) " + obj.Name
+	return findPythonDistInfo(sitePackagesDirs, p.Name, p.Version)
+}
+
+// findPythonDistInfo returns the dist-info directory of the given distribution
+// in the first of sitePackagesDirs that has it. Names are compared after PEP 503
+// normalization and versions after PEP 440 normalization, so "zope.interface"
+// matches "zope_interface-6.0.dist-info" and "Jinja2" matches
+// "jinja2-3.1.4.dist-info".
+func findPythonDistInfo(sitePackagesDirs []string, name, version string) string {
+	wantName := normalizePythonName(name)
+	wantVersion := normalizePythonVersion(version)
+
+	for _, dir := range sitePackagesDirs {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+
+		for _, entry := range entries {
+			if !entry.IsDir() {
+				continue
+			}
+
+			base, ok := strings.CutSuffix(entry.Name(), ".dist-info")
+			if !ok {
+				continue
+			}
+
+			// PEP 427 escapes "-" in the distribution name, so the last "-"
+			// separates the name from the version. Tools that do not escape the
+			// name still produce versions without "-".
+			idx := strings.LastIndex(base, "-")
+			if idx == -1 {
+				continue
+			}
+
+			if normalizePythonName(base[:idx]) == wantName && normalizePythonVersion(base[idx+1:]) == wantVersion {
+				return filepath.Join(dir, entry.Name())
+			}
+		}
 	}
 
 	return ""
 }
 
-// formatAuthorCopyright strips the email and URL fragments from an npm author
-// string and returns a "Copyright (c) <name>" string.
-func formatAuthorCopyright(author string) string {
-	if i := strings.Index(author, " <"); i != -1 {
-		author = author[:i]
+var pythonNameSeparators = regexp.MustCompile(`[-_.]+`)
+
+// normalizePythonName normalizes a distribution name per PEP 503: runs of
+// "-", "_" and "." collapse into a single "-" and the result is lowercased.
+func normalizePythonName(name string) string {
+	return strings.ToLower(pythonNameSeparators.ReplaceAllString(name, "-"))
+}
+
+var pep440Regex = regexp.MustCompile(`^v?(?:(\d+)!)?(\d+(?:\.\d+)*)` +
+	`(?:[-_.]?(a|b|c|rc|alpha|beta|pre|preview)[-_.]?(\d*))?` +
+	`(?:-(\d+)|[-_.]?(post|rev|r)[-_.]?(\d*))?` +
+	`(?:[-_.]?(dev)[-_.]?(\d*))?` +
+	`(?:\+([a-z0-9]+(?:[-_.][a-z0-9]+)*))?$`)
+
+// normalizePythonVersion returns the PEP 440 normalized form of version (e.g.
+// "1.0.0-Alpha.1" becomes "1.0.0a1", "2.0-r3" becomes "2.0.post3"). Versions
+// that are not PEP 440 compliant are only lowercased.
+func normalizePythonVersion(version string) string {
+	v := strings.ToLower(strings.TrimSpace(version))
+
+	m := pep440Regex.FindStringSubmatch(v)
+	if m == nil {
+		return v
+	}
+
+	var b strings.Builder
+
+	if m[1] != "" && trimLeadingZeros(m[1]) != "0" {
+		b.WriteString(trimLeadingZeros(m[1]) + "!")
+	}
+
+	release := strings.Split(m[2], ".")
+	for i, part := range release {
+		release[i] = trimLeadingZeros(part)
+	}
+
+	b.WriteString(strings.Join(release, "."))
+
+	if m[3] != "" {
+		pre := map[string]string{"alpha": "a", "beta": "b", "c": "rc", "pre": "rc", "preview": "rc"}[m[3]]
+		if pre == "" {
+			pre = m[3]
+		}
+
+		b.WriteString(pre + trimLeadingZeros(m[4]))
+	}
+
+	switch {
+	case m[5] != "":
+		b.WriteString(".post" + trimLeadingZeros(m[5]))
+	case m[6] != "":
+		b.WriteString(".post" + trimLeadingZeros(m[7]))
+	}
+
+	if m[8] != "" {
+		b.WriteString(".dev" + trimLeadingZeros(m[9]))
+	}
+
+	if m[10] != "" {
+		b.WriteString("+" + pythonNameSeparators.ReplaceAllString(m[10], "."))
+	}
+
+	return b.String()
+}
+
+// trimLeadingZeros strips the leading zeros of a numeric version segment. An
+// empty segment stands for an implicit zero.
+func trimLeadingZeros(s string) string {
+	s = strings.TrimLeft(s, "0")
+	if s == "" {
+		return "0"
 	}
 
-	if i := strings.Index(author, " ("); i != -1 {
-		author = author[:i]
+	return s
+}
+
+// pythonDistInfoCopyright parses the METADATA file of a dist-info directory as
+// an RFC 822 document and extracts a copyright notice from it.
+func pythonDistInfoCopyright(distInfo string) notice {
+	header := readPythonMetadataHeader(distInfo)
+	if header == nil {
+		return notice{}
 	}
 
-	author = strings.TrimSpace(author)
-	if author == "" {
-		return ""
+	for _, licenseFile := range header[textproto.CanonicalMIMEHeaderKey("License-File")] {
+		licenseFile = strings.TrimSpace(licenseFile)
+		if licenseFile == "" {
+			continue
+		}
+
+		p := pythonLicenseFilePath(distInfo, licenseFile)
+		if p == "" {
+			continue
+		}
+
+		if c := firstCopyrightLine(readFileText(p)); c != "" {
+			return notice{Text: c, Source: "License-File " + licenseFile}
+		}
 	}
 
-	return "Copyright (c) " + author
+	fields := []struct {
+		name  string
+		names func(string) []string
+	}{
+		{name: "Author", names: pythonPersonNames},
+		{name: "Author-email", names: pythonEmailNames},
+		{name: "Maintainer", names: pythonPersonNames},
+		{name: "Maintainer-email", names: pythonEmailNames},
+	}
+
+	for _, field := range fields {
+		if names := field.names(header.Get(field.name)); len(names) > 0 {
+			return notice{
+				Text:   "Copyright (c) " + strings.Join(names, ", "),
+				Source: "METADATA " + field.name,
+			}
+		}
+	}
+
+	return notice{}
 }
 
-// ─── Python ──────────────────────────────────────────────────────────────────
+// readPythonMetadataHeader returns the headers of the METADATA file of a
+// dist-info directory, or nil if it cannot be read.
+func readPythonMetadataHeader(distInfo string) mail.Header {
+	if distInfo == "" {
+		return nil
+	}
 
-// extractPythonCopyright reads the Author field from the dist-info METADATA
-// file for the given PyPI PURL.
-func extractPythonCopyright(sitePackagesDir, purl string) string {
-	if sitePackagesDir == "" {
-		return ""
+	data, err := os.ReadFile(filepath.Join(distInfo, "METADATA"))
+	if err != nil {
+		return nil
 	}
 
-	if idx := strings.Index(purl, "?"); idx != -1 {
-		purl = purl[:idx]
+	msg, err := mail.ReadMessage(bytes.NewReader(data))
+	if err != nil {
+		// Hand-written or old METADATA files are not always valid RFC 822: one
+		// malformed line must not discard the other fields.
+		return scanPythonMetadataHeader(string(data))
 	}
 
-	rest, ok := strings.CutPrefix(purl, "pkg:pypi/")
-	if !ok {
-		return ""
+	return msg.Header
+}
+
+// scanPythonMetadataHeader reads the headers of a METADATA file line by line,
+// up to the first blank line, skipping the lines that are not "Name: value"
+// fields or their continuation.
+func scanPythonMetadataHeader(metadata string) mail.Header {
+	header := mail.Header{}
+
+	var last string
+
+	for line := range strings.SplitSeq(metadata, "\n") {
+		line = strings.TrimRight(line, "\r")
+		if strings.TrimSpace(line) == "" {
+			break
+		}
+
+		if line[0] == ' ' || line[0] == '\t' {
+			if values := header[last]; len(values) > 0 {
+				values[len(values)-1] += " " + strings.TrimSpace(line)
+			}
+
+			continue
+		}
+
+		key, value, ok := strings.Cut(line, ":")
+		if !ok || key == "" || strings.ContainsAny(key, " \t") {
+			last = ""
+
+			continue
+		}
+
+		last = textproto.CanonicalMIMEHeaderKey(key)
+		header[last] = append(header[last], strings.TrimSpace(value))
 	}
 
-	idx := strings.LastIndex(rest, "@")
-	if idx == -1 {
+	return header
+}
+
+// pythonLicenseFilePath returns the path of a License-File entry. PEP 639 puts
+// license files under dist-info/licenses/; older setuptools releases put them
+// at the root of dist-info. It is empty for an entry escaping the dist-info
+// directory.
+func pythonLicenseFilePath(distInfo, licenseFile string) string {
+	if !filepath.IsLocal(filepath.FromSlash(licenseFile)) {
+		log.Warn().
+			Str("dist_info", distInfo).
+			Str("license_file", licenseFile).
+			Msg("License-File outside of the dist-info directory ignored.")
+
 		return ""
 	}
 
-	packageName, version := rest[:idx], rest[idx+1:]
-	if packageName == "" || version == "" {
-		return ""
+	p := filepath.Join(distInfo, "licenses", filepath.FromSlash(licenseFile))
+	if _, err := os.Stat(p); err == nil {
+		return p
 	}
 
-	// dist-info directories use the package name as-is or with hyphens replaced
-	// by underscores depending on the packaging tool that produced them.
-	for _, name := range []string{packageName, strings.ReplaceAll(packageName, "-", "_")} {
-		metadataPath := filepath.Join(sitePackagesDir, name+"-"+version+".dist-info", "METADATA")
-		if c := pythonAuthorCopyright(readFileText(metadataPath)); c != "" {
-			return c
+	return filepath.Join(distInfo, filepath.FromSlash(licenseFile))
+}
+
+// pythonLicenseFilePaths returns the license files of a dist-info directory:
+// the declared License-File entries, or the license files found at its root
+// when none are declared.
+func pythonLicenseFilePaths(distInfo string) []string {
+	header := readPythonMetadataHeader(distInfo)
+	if header == nil {
+		return nil
+	}
+
+	var paths []string
+
+	for _, licenseFile := range header[textproto.CanonicalMIMEHeaderKey("License-File")] {
+		if licenseFile = strings.TrimSpace(licenseFile); licenseFile != "" {
+			if p := pythonLicenseFilePath(distInfo, licenseFile); p != "" {
+				paths = append(paths, p)
+			}
 		}
 	}
 
-	return ""
+	if len(paths) > 0 {
+		return paths
+	}
+
+	return findCaseInsensitiveFiles(distInfo, licenseFileNames)
 }
 
-func pythonAuthorCopyright(metadata string) string {
-	for line := range strings.SplitSeq(metadata, "\n") {
-		author, ok := strings.CutPrefix(strings.TrimSpace(line), "Author: ")
-		if !ok {
-			continue
+// pythonPersonNames returns the value of a free-form METADATA person field,
+// ignoring the "UNKNOWN" placeholder written by old setuptools releases.
+func pythonPersonNames(value string) []string {
+	value = strings.TrimSpace(value)
+	if value == "" || strings.EqualFold(value, "UNKNOWN") {
+		return nil
+	}
+
+	return []string{value}
+}
+
+// pythonEmailNames returns the display names of a METADATA email field
+// ("Name <mail>, Other <mail>"). Bare addresses are kept when no display name
+// is given.
+func pythonEmailNames(value string) []string {
+	value = strings.TrimSpace(value)
+	if value == "" || strings.EqualFold(value, "UNKNOWN") {
+		return nil
+	}
+
+	addresses, err := mail.ParseAddressList(value)
+	if err != nil {
+		if name := personName(value); name != "" {
+			return []string{name}
 		}
 
-		author = strings.TrimSpace(author)
-		if author != "" && !strings.EqualFold(author, "UNKNOWN") {
-			return "Copyright (c) " + author
+		return nil
+	}
+
+	names := make([]string, 0, len(addresses))
+	for _, addr := range addresses {
+		if addr.Name != "" {
+			names = append(names, addr.Name)
+		} else {
+			names = append(names, addr.Address)
 		}
 	}
 
-	return ""
+	return names
 }
 
 // ─── Shared ──────────────────────────────────────────────────────────────────
 
+// licenseFileNames lists the license file names probed in a package directory,
+// in order of preference.
+//
+//nolint:misspell // support British spelling
+var licenseFileNames = []string{
+	"LICENSE", "LICENSE.md", "LICENSE.txt",
+	"LICENCE", "LICENCE.md", "LICENCE.txt",
+	"LICENSE-MIT", "LICENSE-MIT.md", "LICENSE-MIT.txt", "MIT-LICENSE", "MIT-LICENSE.txt",
+	"COPYING", "COPYING.md", "COPYING.txt",
+}
+
+// noticeFileNames lists the NOTICE file names probed in a package directory.
+var noticeFileNames = []string{"NOTICE", "NOTICE.md", "NOTICE.txt"}
+
 // firstCopyrightLine returns the first line in text that starts with "Copyright"
 // (case-insensitive), trimmed of surrounding whitespace.
 func firstCopyrightLine(text string) string {
@@ -294,6 +778,56 @@ func firstCopyrightLine(text string) string {
 	return ""
 }
 
+// personName strips the email and URL fragments from a "Name <email> (url)"
+// person string.
+func personName(person string) string {
+	if i := strings.Index(person, "<"); i != -1 {
+		person = person[:i]
+	}
+
+	if i := strings.Index(person, "("); i != -1 {
+		person = person[:i]
+	}
+
+	return strings.TrimSpace(person)
+}
+
+// fileNotice returns the first copyright line of the file at path, with the
+// file name as its source.
+func fileNotice(path string) notice {
+	c := firstCopyrightLine(readFileText(path))
+	if c == "" {
+		return notice{}
+	}
+
+	return notice{Text: c, Source: filepath.Base(path)}
+}
+
+// readLicenseFiles reads the given license files, skipping unreadable ones.
+func readLicenseFiles(paths []string) []licenseFile {
+	var files []licenseFile
+
+	for _, p := range paths {
+		if text := readFileText(p); text != "" {
+			files = append(files, licenseFile{Name: filepath.Base(p), Text: text})
+		}
+	}
+
+	return files
+}
+
+func isFile(path string) bool {
+	info, err := os.Stat(path)
+
+	return err == nil && info.Mode().IsRegular()
+}
+
+func isDir(path string) bool {
+	info, err := os.Stat(path)
+
+	return err == nil && info.IsDir()
+}
+
 func readFileText(path string) string {
 	data, err := os.ReadFile(path)
 	if err != nil {
@@ -303,15 +837,17 @@ func readFileText(path string) string {
 	return string(data)
 }
 
-// findCaseInsensitiveFile returns the path of the first file in dir whose name
-// matches one of names case-insensitively. Preference follows the order of
-// names: "LICENSE" beats "LICENSE.md" even if the latter appears first in dir.
-func findCaseInsensitiveFile(dir string, names []string) string {
+// findCaseInsensitiveFiles returns the paths of the files in dir whose names
+// match one of names case-insensitively. Results follow the order of names:
+// "LICENSE" comes before "LICENSE.md" even if the latter appears first in dir.
+func findCaseInsensitiveFiles(dir string, names []string) []string {
 	entries, err := os.ReadDir(dir)
 	if err != nil {
-		return ""
+		return nil
 	}
 
+	var found []string
+
 	for _, name := range names {
 		for _, entry := range entries {
 			if entry.IsDir() {
@@ -319,10 +855,10 @@ func findCaseInsensitiveFile(dir string, names []string) string {
 			}
 
 			if strings.EqualFold(entry.Name(), name) {
-				return filepath.Join(dir, entry.Name())
+				found = append(found, filepath.Join(dir, entry.Name()))
 			}
 		}
 	}
 
-	return ""
+	return found
 }
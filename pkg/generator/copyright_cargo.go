@@ -0,0 +1,189 @@
+package generator
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"github.com/rs/zerolog/log"
+)
+
+// cargoLicenseFileNames lists the license file names probed in a crate
+// directory, in order of preference. Crates dual-licensed under MIT and
+// Apache-2.0 conventionally ship LICENSE-MIT and LICENSE-APACHE, and sometimes
+// a COPYRIGHT file.
+var cargoLicenseFileNames = slices.Concat(licenseFileNames, []string{
+	"COPYRIGHT", "COPYRIGHT.md", "COPYRIGHT.txt",
+	"LICENSE-APACHE", "LICENSE-APACHE.md", "LICENSE-APACHE.txt",
+})
+
+// cargoManifest holds the fields of Cargo.toml used for attribution.
+type cargoManifest struct {
+	Package struct {
+		Name        string   `toml:"name"`
+		Version     string   `toml:"version"`
+		Authors     []string `toml:"authors"`
+		License     string   `toml:"license"`
+		LicenseFile string   `toml:"license-file"`
+	} `toml:"package"`
+}
+
+// cargoRegistrySrc returns the directory holding the extracted crates of every
+// registry, respecting CARGO_HOME with the standard fallback to ~/.cargo.
+func cargoRegistrySrc() string {
+	cargoHome := os.Getenv("CARGO_HOME")
+	if cargoHome == "" {
+		cargoHome = filepath.Join(os.Getenv("HOME"), ".cargo")
+	}
+
+	return filepath.Join(cargoHome, "registry", "src")
+}
+
+// resolveCargoVendorDir returns the directory of crates vendored with
+// "cargo vendor". If configured is empty, it uses "vendor" when it holds
+// vendored crates.
+func resolveCargoVendorDir(configured string) string {
+	if configured != "" {
+		return configured
+	}
+
+	if matches, _ := filepath.Glob(filepath.Join("vendor", "*", ".cargo-checksum.json")); len(matches) > 0 {
+		return "vendor"
+	}
+
+	return ""
+}
+
+// parseCargoPURL returns the crate name and version of a cargo PURL.
+func parseCargoPURL(purl string) (string, string) {
+	p, ok := parsePURL(purl)
+	if !ok || p.Type != "cargo" || p.Version == "" {
+		return "", ""
+	}
+
+	return p.Name, p.Version
+}
+
+// cargoCrateDir returns the source directory of the crate identified by the
+// given PURL. Vendored crates take precedence over the registry cache.
+// "cargo vendor" names the directory after the crate alone, unless several
+// versions of it are vendored.
+func cargoCrateDir(registrySrc, vendorDir, purl string) string {
+	name, version := parseCargoPURL(purl)
+	if name == "" || version == "" {
+		return ""
+	}
+
+	if vendorDir != "" {
+		dir := filepath.Join(vendorDir, name+"-"+version)
+		if isDir(dir) {
+			return dir
+		}
+
+		dir = filepath.Join(vendorDir, name)
+		if readCargoManifest(dir).Package.Version == version {
+			return dir
+		}
+	}
+
+	if registrySrc == "" {
+		return ""
+	}
+
+	// Each registry has its own "<host>-<hash>" directory.
+	matches, _ := filepath.Glob(filepath.Join(registrySrc, "*", name+"-"+version))
+	for _, dir := range matches {
+		if isDir(dir) {
+			return dir
+		}
+	}
+
+	return ""
+}
+
+// readCargoManifest reads the Cargo.toml of a crate directory. Published and
+// vendored crates have normalized manifests, so workspace inheritance is not
+// a concern.
+func readCargoManifest(crateDir string) cargoManifest {
+	var manifest cargoManifest
+
+	if _, err := toml.DecodeFile(filepath.Join(crateDir, "Cargo.toml"), &manifest); err != nil {
+		return cargoManifest{}
+	}
+
+	return manifest
+}
+
+// extractCargoCopyright reads the copyright notice for a crate: license files
+// first, then the authors field of Cargo.toml.
+func extractCargoCopyright(registrySrc, vendorDir, purl string) notice {
+	crateDir := cargoCrateDir(registrySrc, vendorDir, purl)
+	if crateDir == "" {
+		return notice{}
+	}
+
+	for _, filename := range cargoLicenseFilePaths(crateDir) {
+		if n := fileNotice(filename); n.Text != "" {
+			return n
+		}
+	}
+
+	var names []string
+
+	for _, author := range readCargoManifest(crateDir).Package.Authors {
+		if name := personName(author); name != "" {
+			names = append(names, name)
+		}
+	}
+
+	if len(names) == 0 {
+		return notice{}
+	}
+
+	return notice{Text: "Copyright (c) " + strings.Join(names, ", "), Source: "Cargo.toml authors"}
+}
+
+// cargoLicenseFilePaths returns the license files of a crate, starting with the
+// custom one declared by the license-file field of Cargo.toml, which must be
+// inside the crate directory.
+func cargoLicenseFilePaths(crateDir string) []string {
+	if crateDir == "" {
+		return nil
+	}
+
+	var paths []string
+
+	if lf := readCargoManifest(crateDir).Package.LicenseFile; lf != "" {
+		if filepath.IsLocal(filepath.FromSlash(lf)) {
+			paths = append(paths, filepath.Join(crateDir, filepath.FromSlash(lf)))
+		} else {
+			log.Warn().
+				Str("crate_dir", crateDir).
+				Str("license_file", lf).
+				Msg("Cargo.toml license-file outside of the crate directory ignored.")
+		}
+	}
+
+	for _, p := range findCaseInsensitiveFiles(crateDir, cargoLicenseFileNames) {
+		if !slices.Contains(paths, p) {
+			paths = append(paths, p)
+		}
+	}
+
+	return paths
+}
+
+// cargoDeclaredLicense returns the license expression declared in Cargo.toml.
+// The legacy "MIT/Apache-2.0" form is turned into an SPDX OR expression.
+func cargoDeclaredLicense(registrySrc, vendorDir, purl string) string {
+	crateDir := cargoCrateDir(registrySrc, vendorDir, purl)
+	if crateDir == "" {
+		return ""
+	}
+
+	license := strings.TrimSpace(readCargoManifest(crateDir).Package.License)
+
+	return strings.ReplaceAll(license, "/", " OR ")
+}
@@ -0,0 +1,115 @@
+package generator
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeCrate(t *testing.T, dir, manifest string, files map[string]string) {
+	t.Helper()
+
+	require.NoError(t, os.MkdirAll(dir, 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "Cargo.toml"), []byte(manifest), 0o644))
+
+	for name, content := range files {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644))
+	}
+}
+
+func TestParseCargoPURL(t *testing.T) {
+	t.Parallel()
+
+	name, version := parseCargoPURL("pkg:cargo/serde@1.0.197?checksum=sha256:abc")
+	assert.Equal(t, "serde", name)
+	assert.Equal(t, "1.0.197", version)
+
+	name, _ = parseCargoPURL("pkg:npm/serde@1.0.197")
+	assert.Empty(t, name)
+}
+
+func TestExtractCargoCopyright_Registry(t *testing.T) {
+	t.Parallel()
+
+	registrySrc := t.TempDir()
+	writeCrate(t, filepath.Join(registrySrc, "index.crates.io-6f17d22bba15001f", "serde-1.0.197"),
+		"[package]\nname = \"serde\"\nversion = \"1.0.197\"\nlicense = \"MIT OR Apache-2.0\"\n",
+		map[string]string{
+			"LICENSE-APACHE": "Apache License\nVersion 2.0, January 2004",
+			"LICENSE-MIT":    "Copyright (c) David Tolnay\n\nPermission is hereby granted...",
+		})
+
+	got := extractCargoCopyright(registrySrc, "", "pkg:cargo/serde@1.0.197")
+	assert.Equal(t, notice{Text: "Copyright (c) David Tolnay", Source: "LICENSE-MIT"}, got)
+}
+
+func TestExtractCargoCopyright_Authors(t *testing.T) {
+	t.Parallel()
+
+	registrySrc := t.TempDir()
+	writeCrate(t, filepath.Join(registrySrc, "index.crates.io-6f17d22bba15001f", "itoa-1.0.10"),
+		"[package]\nname = \"itoa\"\nversion = \"1.0.10\"\nauthors = [\"David Tolnay <dtolnay@gmail.com>\", \"Acme Corp\"]\n",
+		nil)
+
+	got := extractCargoCopyright(registrySrc, "", "pkg:cargo/itoa@1.0.10")
+	assert.Equal(t, notice{Text: "Copyright (c) David Tolnay, Acme Corp", Source: "Cargo.toml authors"}, got)
+}
+
+func TestCargoCrateDir_Vendored(t *testing.T) {
+	t.Parallel()
+
+	vendorDir := t.TempDir()
+	writeCrate(t, filepath.Join(vendorDir, "libc"), "[package]\nname = \"libc\"\nversion = \"0.2.153\"\n", nil)
+	writeCrate(t, filepath.Join(vendorDir, "syn-1.0.109"), "[package]\nname = \"syn\"\nversion = \"1.0.109\"\n", nil)
+	writeCrate(t, filepath.Join(vendorDir, "syn"), "[package]\nname = \"syn\"\nversion = \"2.0.52\"\n", nil)
+
+	assert.Equal(t, filepath.Join(vendorDir, "libc"), cargoCrateDir("", vendorDir, "pkg:cargo/libc@0.2.153"))
+	assert.Equal(t, filepath.Join(vendorDir, "syn-1.0.109"), cargoCrateDir("", vendorDir, "pkg:cargo/syn@1.0.109"))
+	assert.Equal(t, filepath.Join(vendorDir, "syn"), cargoCrateDir("", vendorDir, "pkg:cargo/syn@2.0.52"))
+	assert.Empty(t, cargoCrateDir("", vendorDir, "pkg:cargo/libc@0.2.152"))
+}
+
+func TestCargoDeclaredLicense(t *testing.T) {
+	t.Parallel()
+
+	vendorDir := t.TempDir()
+	writeCrate(t, filepath.Join(vendorDir, "old"), "[package]\nname = \"old\"\nversion = \"0.1.0\"\nlicense = \"MIT/Apache-2.0\"\n", nil)
+	writeCrate(t, filepath.Join(vendorDir, "custom"), "[package]\nname = \"custom\"\nversion = \"0.1.0\"\nlicense-file = \"LICENSE.custom\"\n",
+		map[string]string{"LICENSE.custom": "Copyright 2024 Custom Inc."})
+
+	assert.Equal(t, "MIT OR Apache-2.0", cargoDeclaredLicense("", vendorDir, "pkg:cargo/old@0.1.0"))
+	assert.Empty(t, cargoDeclaredLicense("", vendorDir, "pkg:cargo/custom@0.1.0"))
+
+	e := copyrightEnricher{cargoVendorDir: vendorDir}
+	assert.Equal(t, []licenseFile{{Name: "LICENSE.custom", Text: "Copyright 2024 Custom Inc."}}, e.licenseFiles("pkg:cargo/custom@0.1.0"))
+	assert.Equal(t, notice{Text: "Copyright 2024 Custom Inc.", Source: "LICENSE.custom"}, e.enrich("pkg:cargo/custom@0.1.0", ""))
+}
+
+func TestCargoLicenseFilePaths(t *testing.T) {
+	t.Parallel()
+
+	vendorDir := t.TempDir()
+	writeCrate(t, filepath.Join(vendorDir, "dup"), "[package]\nname = \"dup\"\nversion = \"0.1.0\"\nlicense-file = \"LICENSE\"\n",
+		map[string]string{"LICENSE": "Copyright 2024 Dup Inc."})
+	writeCrate(t, filepath.Join(vendorDir, "escape"), "[package]\nname = \"escape\"\nversion = \"0.1.0\"\nlicense-file = \"../dup/LICENSE\"\n", nil)
+
+	assert.Equal(t, []string{filepath.Join(vendorDir, "dup", "LICENSE")}, cargoLicenseFilePaths(filepath.Join(vendorDir, "dup")))
+	assert.Empty(t, cargoLicenseFilePaths(filepath.Join(vendorDir, "escape")))
+}
+
+func TestBuildIndex_CargoDeclaredLicense(t *testing.T) {
+	t.Parallel()
+
+	vendorDir := t.TempDir()
+	writeCrate(t, filepath.Join(vendorDir, "old"), "[package]\nname = \"old\"\nversion = \"0.1.0\"\nlicense = \"MIT/Apache-2.0\"\n", nil)
+
+	components := []Component{{Name: "old", Version: "0.1.0", PURL: "pkg:cargo/old@0.1.0"}}
+
+	_, byKey := buildIndex(components, inputs{}, copyrightEnricher{cargoVendorDir: vendorDir}, nil)
+
+	assert.Equal(t, []string{"Apache-2.0", "MIT"}, byKey["pkg:cargo/old@0.1.0"].LicenseIDs)
+	assert.Equal(t, licenseSourceMetadata, byKey["pkg:cargo/old@0.1.0"].LicenseSource)
+}
@@ -0,0 +1,167 @@
+package generator
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// composerPackage holds the fields of composer.json, or of an entry of
+// vendor/composer/installed.json, used for attribution.
+type composerPackage struct {
+	Name        string          `json:"name"`
+	License     json.RawMessage `json:"license"`
+	InstallPath string          `json:"install-path"`
+	Authors     []struct {
+		Name string `json:"name"`
+	} `json:"authors"`
+}
+
+// resolveComposerVendorDir returns the Composer vendor directory. If configured
+// is empty, it uses "vendor" when it was populated by Composer.
+func resolveComposerVendorDir(configured string) string {
+	if configured != "" {
+		return configured
+	}
+
+	if _, err := os.Stat(filepath.Join("vendor", "composer", "installed.json")); err == nil {
+		return "vendor"
+	}
+
+	return ""
+}
+
+// parseComposerPURL returns the "<vendor>/<package>" name of a composer PURL.
+func parseComposerPURL(purl string) string {
+	p, ok := parsePURL(purl)
+	if !ok || p.Type != "composer" || p.Namespace == "" {
+		return ""
+	}
+
+	return p.fullName()
+}
+
+// readComposerInstalled returns the entry of vendor/composer/installed.json for
+// the given package. Composer 1 writes a bare list of packages, Composer 2 an
+// object with a "packages" list.
+func readComposerInstalled(vendorDir, name string) (composerPackage, bool) {
+	data, err := os.ReadFile(filepath.Join(vendorDir, "composer", "installed.json"))
+	if err != nil {
+		return composerPackage{}, false
+	}
+
+	var installed struct {
+		Packages []composerPackage `json:"packages"`
+	}
+	if err := json.Unmarshal(data, &installed); err != nil {
+		if err := json.Unmarshal(data, &installed.Packages); err != nil {
+			return composerPackage{}, false
+		}
+	}
+
+	for _, pkg := range installed.Packages {
+		if strings.EqualFold(pkg.Name, name) {
+			return pkg, true
+		}
+	}
+
+	return composerPackage{}, false
+}
+
+// composerPackageDir returns the installation directory and the metadata of the
+// package identified by the given PURL. The package's own composer.json takes
+// precedence over its installed.json entry. A project has a single version of
+// each package, so the PURL version is not checked.
+func composerPackageDir(vendorDir, purl string) (string, composerPackage) {
+	name := parseComposerPURL(purl)
+	if vendorDir == "" || name == "" {
+		return "", composerPackage{}
+	}
+
+	dir := filepath.Join(vendorDir, filepath.FromSlash(name))
+
+	installed, ok := readComposerInstalled(vendorDir, name)
+	if ok && installed.InstallPath != "" {
+		// install-path is relative to vendor/composer.
+		dir = filepath.Join(vendorDir, "composer", filepath.FromSlash(installed.InstallPath))
+	}
+
+	if data, err := os.ReadFile(filepath.Join(dir, "composer.json")); err == nil {
+		var pkg composerPackage
+		if err := json.Unmarshal(data, &pkg); err == nil {
+			return dir, pkg
+		}
+	}
+
+	if !isDir(dir) {
+		dir = ""
+	}
+
+	return dir, installed
+}
+
+// extractComposerCopyright reads the copyright notice for a Composer package:
+// license files first, then the authors of composer.json.
+func extractComposerCopyright(vendorDir, purl string) notice {
+	dir, pkg := composerPackageDir(vendorDir, purl)
+
+	for _, filename := range findCaseInsensitiveFiles(dir, licenseFileNames) {
+		if n := fileNotice(filename); n.Text != "" {
+			return n
+		}
+	}
+
+	var names []string
+
+	for _, author := range pkg.Authors {
+		if name := strings.TrimSpace(author.Name); name != "" {
+			names = append(names, name)
+		}
+	}
+
+	if len(names) == 0 {
+		return notice{}
+	}
+
+	return notice{Text: "Copyright (c) " + strings.Join(names, ", "), Source: "composer.json authors"}
+}
+
+// composerLicenseFilePaths returns the license files of a Composer package.
+func composerLicenseFilePaths(vendorDir, purl string) []string {
+	dir, _ := composerPackageDir(vendorDir, purl)
+	if dir == "" {
+		return nil
+	}
+
+	return findCaseInsensitiveFiles(dir, licenseFileNames)
+}
+
+// composerDeclaredLicense returns the license declared in composer.json. The
+// field holds an SPDX expression or a list of licenses to choose from.
+func composerDeclaredLicense(vendorDir, purl string) string {
+	_, pkg := composerPackageDir(vendorDir, purl)
+	if len(pkg.License) == 0 {
+		return ""
+	}
+
+	var license string
+	if err := json.Unmarshal(pkg.License, &license); err == nil {
+		return strings.TrimSpace(license)
+	}
+
+	var licenses []string
+	if err := json.Unmarshal(pkg.License, &licenses); err != nil {
+		return ""
+	}
+
+	var exprs []string
+
+	for _, l := range licenses {
+		if l = strings.TrimSpace(l); l != "" {
+			exprs = append(exprs, l)
+		}
+	}
+
+	return strings.Join(exprs, " OR ")
+}
@@ -0,0 +1,82 @@
+package generator
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseComposerPURL(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "symfony/console", parseComposerPURL("pkg:composer/symfony/console@v6.4.4"))
+	assert.Equal(t, "psr/log", parseComposerPURL("pkg:composer/psr/log@3.0.0?foo=bar"))
+	assert.Empty(t, parseComposerPURL("pkg:composer/console@v6.4.4"))
+	assert.Empty(t, parseComposerPURL("pkg:npm/symfony/console@v6.4.4"))
+}
+
+func TestExtractComposerCopyright_LicenseFile(t *testing.T) {
+	t.Parallel()
+
+	vendorDir := t.TempDir()
+	writeTestFile(t, vendorDir, "symfony/console/LICENSE", "Copyright (c) 2004-present Fabien Potencier\n\nPermission is hereby granted...")
+	writeTestFile(t, vendorDir, "symfony/console/composer.json", `{"name": "symfony/console", "license": "MIT", "authors": [{"name": "Fabien Potencier"}]}`)
+
+	got := extractComposerCopyright(vendorDir, "pkg:composer/symfony/console@v6.4.4")
+	assert.Equal(t, notice{Text: "Copyright (c) 2004-present Fabien Potencier", Source: "LICENSE"}, got)
+}
+
+func TestExtractComposerCopyright_Authors(t *testing.T) {
+	t.Parallel()
+
+	vendorDir := t.TempDir()
+	writeTestFile(t, vendorDir, "psr/log/composer.json",
+		`{"name": "psr/log", "license": "MIT", "authors": [{"name": "PHP-FIG", "homepage": "https://www.php-fig.org/"}]}`)
+
+	got := extractComposerCopyright(vendorDir, "pkg:composer/psr/log@3.0.0")
+	assert.Equal(t, notice{Text: "Copyright (c) PHP-FIG", Source: "composer.json authors"}, got)
+}
+
+func TestComposerPackageDir_InstalledJSON(t *testing.T) {
+	t.Parallel()
+
+	// Composer 2 layout: the package directory has no composer.json of its own
+	// and lives at the install-path recorded in installed.json.
+	vendorDir := t.TempDir()
+	writeTestFile(t, vendorDir, "composer/installed.json", `{
+		"packages": [
+			{"name": "monolog/monolog", "license": ["MIT"], "authors": [{"name": "Jordi Boggiano"}], "install-path": "../monolog/monolog"}
+		],
+		"dev": true
+	}`)
+	writeTestFile(t, vendorDir, "monolog/monolog/README.md", "Monolog")
+
+	dir, pkg := composerPackageDir(vendorDir, "pkg:composer/monolog/monolog@3.5.0")
+	assert.Equal(t, filepath.Join(vendorDir, "monolog", "monolog"), dir)
+	assert.Equal(t, "monolog/monolog", pkg.Name)
+
+	got := extractComposerCopyright(vendorDir, "pkg:composer/monolog/monolog@3.5.0")
+	assert.Equal(t, notice{Text: "Copyright (c) Jordi Boggiano", Source: "composer.json authors"}, got)
+}
+
+func TestComposerPackageDir_InstalledJSONv1(t *testing.T) {
+	t.Parallel()
+
+	vendorDir := t.TempDir()
+	writeTestFile(t, vendorDir, "composer/installed.json", `[{"name": "psr/container", "license": ["MIT"]}]`)
+
+	assert.Equal(t, "MIT", composerDeclaredLicense(vendorDir, "pkg:composer/psr/container@1.1.2"))
+}
+
+func TestComposerDeclaredLicense(t *testing.T) {
+	t.Parallel()
+
+	vendorDir := t.TempDir()
+	writeTestFile(t, vendorDir, "a/single/composer.json", `{"license": "(LGPL-2.1-only or GPL-3.0-or-later)"}`)
+	writeTestFile(t, vendorDir, "a/list/composer.json", `{"license": ["LGPL-2.1-only", "GPL-3.0-or-later"]}`)
+
+	assert.Equal(t, "(LGPL-2.1-only or GPL-3.0-or-later)", composerDeclaredLicense(vendorDir, "pkg:composer/a/single@1.0.0"))
+	assert.Equal(t, "LGPL-2.1-only OR GPL-3.0-or-later", composerDeclaredLicense(vendorDir, "pkg:composer/a/list@1.0.0"))
+	assert.Empty(t, composerDeclaredLicense(vendorDir, "pkg:composer/a/missing@1.0.0"))
+}
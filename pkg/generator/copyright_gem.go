@@ -0,0 +1,167 @@
+package generator
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+var (
+	// gemspecListRegex matches the authors/licenses assignments of an installed
+	// gemspec, e.g. `s.authors = ["David Heinemeier Hansson".freeze]`, as well
+	// as their singular forms, e.g. `s.license = "MIT"`.
+	gemspecListRegex = regexp.MustCompile(`(?m)^\s*\w+\.(authors?|licenses?)\s*=\s*(\[[^\]]*\]|"[^"]*"|'[^']*')`)
+	gemspecItemRegex = regexp.MustCompile(`"([^"]*)"|'([^']*)'`)
+)
+
+// gemspec holds the fields of a gemspec used for attribution.
+type gemspec struct {
+	Authors  []string
+	Licenses []string
+}
+
+// resolveGemHome returns the directory holding the installed gems. If
+// configured is empty, it uses GEM_HOME, then the gems installed by
+// "bundle install --deployment" in vendor/bundle.
+func resolveGemHome(configured string) string {
+	if configured != "" {
+		return configured
+	}
+
+	if v := os.Getenv("GEM_HOME"); v != "" {
+		return v
+	}
+
+	if matches, _ := filepath.Glob(filepath.Join("vendor", "bundle", "ruby", "*", "specifications")); len(matches) > 0 {
+		return filepath.Dir(matches[0])
+	}
+
+	return ""
+}
+
+// parseGemPURL returns the name, version and platform of a gem PURL.
+func parseGemPURL(purl string) (string, string, string) {
+	p, ok := parsePURL(purl)
+	if !ok || p.Type != "gem" || p.Version == "" {
+		return "", "", ""
+	}
+
+	return p.Name, p.Version, p.Qualifiers["platform"]
+}
+
+// gemFullName returns the "<name>-<version>[-<platform>]" name RubyGems uses for
+// the installation directory and the gemspec of the gem identified by purl.
+// Platform-specific gems installed without a platform qualifier in the PURL are
+// matched through their specification.
+func gemFullName(gemHome, purl string) string {
+	name, version, platform := parseGemPURL(purl)
+	if gemHome == "" || name == "" || version == "" {
+		return ""
+	}
+
+	fullName := name + "-" + version
+	if platform != "" && platform != "ruby" {
+		fullName += "-" + platform
+	}
+
+	if _, err := os.Stat(filepath.Join(gemHome, "specifications", fullName+".gemspec")); err == nil {
+		return fullName
+	}
+
+	if platform == "" {
+		matches, _ := filepath.Glob(filepath.Join(gemHome, "specifications", fullName+"-*.gemspec"))
+		if len(matches) > 0 {
+			return strings.TrimSuffix(filepath.Base(matches[0]), ".gemspec")
+		}
+	}
+
+	if isDir(filepath.Join(gemHome, "gems", fullName)) {
+		return fullName
+	}
+
+	return ""
+}
+
+// readGemspec reads the authors and licenses of an installed gemspec. Installed
+// gemspecs are generated by RubyGems, so their layout is regular enough to be
+// read without evaluating Ruby.
+func readGemspec(path string) gemspec {
+	var spec gemspec
+
+	for _, m := range gemspecListRegex.FindAllStringSubmatch(readFileText(path), -1) {
+		var values []string
+
+		for _, item := range gemspecItemRegex.FindAllStringSubmatch(m[2], -1) {
+			if v := strings.TrimSpace(item[1] + item[2]); v != "" {
+				values = append(values, v)
+			}
+		}
+
+		if strings.HasPrefix(m[1], "author") {
+			spec.Authors = append(spec.Authors, values...)
+		} else {
+			spec.Licenses = append(spec.Licenses, values...)
+		}
+	}
+
+	return spec
+}
+
+// extractGemCopyright reads the copyright notice for a gem: license files of
+// the installed gem first, then the authors of its gemspec.
+func extractGemCopyright(gemHome, purl string) notice {
+	fullName := gemFullName(gemHome, purl)
+	if fullName == "" {
+		return notice{}
+	}
+
+	for _, filename := range findCaseInsensitiveFiles(filepath.Join(gemHome, "gems", fullName), licenseFileNames) {
+		if n := fileNotice(filename); n.Text != "" {
+			return n
+		}
+	}
+
+	var names []string
+
+	for _, author := range readGemspec(filepath.Join(gemHome, "specifications", fullName+".gemspec")).Authors {
+		if name := personName(author); name != "" {
+			names = append(names, name)
+		}
+	}
+
+	if len(names) == 0 {
+		return notice{}
+	}
+
+	return notice{Text: "Copyright (c) " + strings.Join(names, ", "), Source: "gemspec authors"}
+}
+
+// gemLicenseFilePaths returns the license files of an installed gem.
+func gemLicenseFilePaths(gemHome, purl string) []string {
+	fullName := gemFullName(gemHome, purl)
+	if fullName == "" {
+		return nil
+	}
+
+	return findCaseInsensitiveFiles(filepath.Join(gemHome, "gems", fullName), licenseFileNames)
+}
+
+// gemDeclaredLicense returns the license expression declared in the gemspec.
+// Several licenses are a choice offered by the authors, like in composer.json.
+func gemDeclaredLicense(gemHome, purl string) string {
+	fullName := gemFullName(gemHome, purl)
+	if fullName == "" {
+		return ""
+	}
+
+	var exprs []string
+
+	for _, l := range readGemspec(filepath.Join(gemHome, "specifications", fullName+".gemspec")).Licenses {
+		if l = strings.TrimSpace(l); l != "" {
+			exprs = append(exprs, l)
+		}
+	}
+
+	return strings.Join(exprs, " OR ")
+}
@@ -0,0 +1,91 @@
+package generator
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const railsGemspec = `# -*- encoding: utf-8 -*-
+# stub: rails 7.1.3 ruby lib
+
+Gem::Specification.new do |s|
+  s.name = "rails".freeze
+  s.version = "7.1.3".freeze
+
+  s.required_rubygems_version = Gem::Requirement.new(">= 1.8.11".freeze) if s.respond_to? :required_rubygems_version=
+  s.authors = ["David Heinemeier Hansson".freeze]
+  s.homepage = "https://rubyonrails.org".freeze
+  s.licenses = ["MIT".freeze]
+end
+`
+
+func TestParseGemPURL(t *testing.T) {
+	t.Parallel()
+
+	name, version, platform := parseGemPURL("pkg:gem/nokogiri@1.16.2?platform=x86_64-linux")
+	assert.Equal(t, "nokogiri", name)
+	assert.Equal(t, "1.16.2", version)
+	assert.Equal(t, "x86_64-linux", platform)
+
+	name, _, _ = parseGemPURL("pkg:npm/nokogiri@1.16.2")
+	assert.Empty(t, name)
+}
+
+func TestReadGemspec(t *testing.T) {
+	t.Parallel()
+
+	gemHome := t.TempDir()
+	writeTestFile(t, gemHome, "specifications/rails-7.1.3.gemspec", railsGemspec)
+	writeTestFile(t, gemHome, "specifications/old-1.0.0.gemspec", "Gem::Specification.new do |s|\n  s.author = 'Jane Doe'\n  s.license = 'Ruby'\nend\n")
+
+	assert.Equal(t, gemspec{Authors: []string{"David Heinemeier Hansson"}, Licenses: []string{"MIT"}},
+		readGemspec(filepath.Join(gemHome, "specifications", "rails-7.1.3.gemspec")))
+	assert.Equal(t, gemspec{Authors: []string{"Jane Doe"}, Licenses: []string{"Ruby"}},
+		readGemspec(filepath.Join(gemHome, "specifications", "old-1.0.0.gemspec")))
+}
+
+func TestExtractGemCopyright_LicenseFile(t *testing.T) {
+	t.Parallel()
+
+	gemHome := t.TempDir()
+	writeTestFile(t, gemHome, "specifications/rails-7.1.3.gemspec", railsGemspec)
+	writeTestFile(t, gemHome, "gems/rails-7.1.3/MIT-LICENSE", "Copyright (c) David Heinemeier Hansson\n\nPermission is hereby granted...")
+
+	got := extractGemCopyright(gemHome, "pkg:gem/rails@7.1.3")
+	assert.Equal(t, notice{Text: "Copyright (c) David Heinemeier Hansson", Source: "MIT-LICENSE"}, got)
+	assert.Equal(t, "MIT", gemDeclaredLicense(gemHome, "pkg:gem/rails@7.1.3"))
+}
+
+func TestGemDeclaredLicense_Choice(t *testing.T) {
+	t.Parallel()
+
+	gemHome := t.TempDir()
+	writeTestFile(t, gemHome, "specifications/json-2.7.1.gemspec", "Gem::Specification.new do |s|\n  s.licenses = [\"Ruby\".freeze, \"BSD-2-Clause\".freeze]\nend\n")
+
+	assert.Equal(t, "Ruby OR BSD-2-Clause", gemDeclaredLicense(gemHome, "pkg:gem/json@2.7.1"))
+	assert.Equal(t, []string{"Ruby OR BSD-2-Clause"}, copyrightEnricher{gemHome: gemHome}.declaredLicenses("pkg:gem/json@2.7.1"))
+	assert.Empty(t, gemDeclaredLicense(gemHome, "pkg:gem/json@2.7.0"))
+}
+
+func TestExtractGemCopyright_Authors(t *testing.T) {
+	t.Parallel()
+
+	gemHome := t.TempDir()
+	writeTestFile(t, gemHome, "specifications/rails-7.1.3.gemspec", railsGemspec)
+
+	got := extractGemCopyright(gemHome, "pkg:gem/rails@7.1.3")
+	assert.Equal(t, notice{Text: "Copyright (c) David Heinemeier Hansson", Source: "gemspec authors"}, got)
+}
+
+func TestGemFullName_Platform(t *testing.T) {
+	t.Parallel()
+
+	gemHome := t.TempDir()
+	writeTestFile(t, gemHome, "specifications/nokogiri-1.16.2-x86_64-linux.gemspec", "")
+
+	assert.Equal(t, "nokogiri-1.16.2-x86_64-linux", gemFullName(gemHome, "pkg:gem/nokogiri@1.16.2?platform=x86_64-linux"))
+	assert.Equal(t, "nokogiri-1.16.2-x86_64-linux", gemFullName(gemHome, "pkg:gem/nokogiri@1.16.2"))
+	assert.Empty(t, gemFullName(gemHome, "pkg:gem/nokogiri@1.16.1"))
+}
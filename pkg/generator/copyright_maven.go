@@ -0,0 +1,297 @@
+package generator
+
+import (
+	"archive/zip"
+	"encoding/xml"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// mavenMaxParentDepth bounds the parent POM chain walk.
+const mavenMaxParentDepth = 10
+
+// mavenCoordinates identifies a Maven artifact.
+type mavenCoordinates struct {
+	GroupID    string `xml:"groupId"`
+	ArtifactID string `xml:"artifactId"`
+	Version    string `xml:"version"`
+}
+
+// mavenPOM holds the fields of a POM used for attribution.
+type mavenPOM struct {
+	mavenCoordinates
+
+	Parent       mavenCoordinates `xml:"parent"`
+	Organization struct {
+		Name string `xml:"name"`
+	} `xml:"organization"`
+	Licenses []struct {
+		Name string `xml:"name"`
+		URL  string `xml:"url"`
+	} `xml:"licenses>license"`
+	Developers []struct {
+		Name         string `xml:"name"`
+		Organization string `xml:"organization"`
+	} `xml:"developers>developer"`
+}
+
+// mavenRepository locates artifacts in the local Maven repository and in the
+// Gradle module cache.
+type mavenRepository struct {
+	m2Dir     string
+	gradleDir string
+}
+
+// resolveMavenRepository returns the local repositories to use. If configured
+// is empty, the Maven repository defaults to ~/.m2/repository. The Gradle cache
+// respects GRADLE_USER_HOME with the standard fallback to ~/.gradle.
+func resolveMavenRepository(configured string) mavenRepository {
+	home := os.Getenv("HOME")
+
+	m2Dir := configured
+	if m2Dir == "" {
+		m2Dir = filepath.Join(home, ".m2", "repository")
+	}
+
+	gradleHome := os.Getenv("GRADLE_USER_HOME")
+	if gradleHome == "" {
+		gradleHome = filepath.Join(home, ".gradle")
+	}
+
+	return mavenRepository{
+		m2Dir:     m2Dir,
+		gradleDir: filepath.Join(gradleHome, "caches", "modules-2", "files-2.1"),
+	}
+}
+
+// file returns the path of the artifact file with the given extension ("pom"
+// or "jar"), or an empty string if it is in neither repository.
+func (r mavenRepository) file(coords mavenCoordinates, ext string) string {
+	if coords.GroupID == "" || coords.ArtifactID == "" || coords.Version == "" {
+		return ""
+	}
+
+	name := coords.ArtifactID + "-" + coords.Version + "." + ext
+
+	if r.m2Dir != "" {
+		p := filepath.Join(r.m2Dir, filepath.FromSlash(strings.ReplaceAll(coords.GroupID, ".", "/")), coords.ArtifactID, coords.Version, name)
+		if _, err := os.Stat(p); err == nil {
+			return p
+		}
+	}
+
+	if r.gradleDir != "" {
+		// Gradle stores each file under a directory named after its SHA-1.
+		matches, _ := filepath.Glob(filepath.Join(r.gradleDir, coords.GroupID, coords.ArtifactID, coords.Version, "*", name))
+		if len(matches) > 0 {
+			return matches[0]
+		}
+	}
+
+	return ""
+}
+
+// parseMavenPURL returns the coordinates of a maven PURL.
+func parseMavenPURL(purl string) mavenCoordinates {
+	p, ok := parsePURL(purl)
+	if !ok || p.Type != "maven" || p.Namespace == "" || p.Version == "" {
+		return mavenCoordinates{}
+	}
+
+	return mavenCoordinates{GroupID: p.Namespace, ArtifactID: p.Name, Version: p.Version}
+}
+
+// readMavenPOM reads the POM of the given artifact and fills in the licenses,
+// organization and developers it inherits from its parent POM chain.
+func readMavenPOM(repo mavenRepository, coords mavenCoordinates) (mavenPOM, bool) {
+	pom, ok := decodeMavenPOM(repo.file(coords, "pom"))
+	if !ok {
+		return mavenPOM{}, false
+	}
+
+	parent := pom.Parent
+
+	for range mavenMaxParentDepth {
+		if parent.ArtifactID == "" {
+			break
+		}
+
+		p, ok := decodeMavenPOM(repo.file(parent, "pom"))
+		if !ok {
+			break
+		}
+
+		if len(pom.Licenses) == 0 {
+			pom.Licenses = p.Licenses
+		}
+
+		if pom.Organization.Name == "" {
+			pom.Organization = p.Organization
+		}
+
+		if len(pom.Developers) == 0 {
+			pom.Developers = p.Developers
+		}
+
+		parent = p.Parent
+	}
+
+	return pom, true
+}
+
+func decodeMavenPOM(path string) (mavenPOM, bool) {
+	if path == "" {
+		return mavenPOM{}, false
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return mavenPOM{}, false
+	}
+
+	var pom mavenPOM
+	if err := xml.Unmarshal(data, &pom); err != nil {
+		return mavenPOM{}, false
+	}
+
+	return pom, true
+}
+
+// readMavenJARFiles returns the META-INF license and notice files of a JAR.
+func readMavenJARFiles(jarPath string) (licenses, notices []licenseFile) {
+	if jarPath == "" {
+		return nil, nil
+	}
+
+	r, err := zip.OpenReader(jarPath)
+	if err != nil {
+		return nil, nil
+	}
+
+	defer func() { _ = r.Close() }()
+
+	for _, f := range r.File {
+		dir, base := path.Split(f.Name)
+		if !strings.EqualFold(dir, "META-INF/") {
+			continue
+		}
+
+		upper := strings.ToUpper(base)
+
+		//nolint:misspell // support British spelling
+		isLicense := strings.HasPrefix(upper, "LICENSE") || strings.HasPrefix(upper, "LICENCE")
+		isNotice := strings.HasPrefix(upper, "NOTICE")
+
+		if !isLicense && !isNotice {
+			continue
+		}
+
+		text := readZipFileText(f)
+		if text == "" {
+			continue
+		}
+
+		if isNotice {
+			notices = append(notices, licenseFile{Name: f.Name, Text: text})
+		} else {
+			licenses = append(licenses, licenseFile{Name: f.Name, Text: text})
+		}
+	}
+
+	return licenses, notices
+}
+
+func readZipFileText(f *zip.File) string {
+	rc, err := f.Open()
+	if err != nil {
+		return ""
+	}
+
+	defer func() { _ = rc.Close() }()
+
+	data, err := io.ReadAll(rc)
+	if err != nil {
+		return ""
+	}
+
+	return string(data)
+}
+
+// extractMavenCopyright reads the copyright notice for a Maven artifact: the
+// META-INF/NOTICE and META-INF/LICENSE files of its JAR first, then the
+// organization and developers of its POM.
+func extractMavenCopyright(repo mavenRepository, purl string) notice {
+	coords := parseMavenPURL(purl)
+
+	licenses, notices := readMavenJARFiles(repo.file(coords, "jar"))
+	for _, f := range append(notices, licenses...) {
+		if c := firstCopyrightLine(f.Text); c != "" {
+			return notice{Text: c, Source: f.Name}
+		}
+	}
+
+	pom, ok := readMavenPOM(repo, coords)
+	if !ok {
+		return notice{}
+	}
+
+	if name := strings.TrimSpace(pom.Organization.Name); name != "" {
+		return notice{Text: "Copyright (c) " + name, Source: "pom.xml organization"}
+	}
+
+	var names []string
+
+	for _, dev := range pom.Developers {
+		name := strings.TrimSpace(dev.Name)
+		if name == "" {
+			name = strings.TrimSpace(dev.Organization)
+		}
+
+		if name != "" {
+			names = append(names, name)
+		}
+	}
+
+	if len(names) == 0 {
+		return notice{}
+	}
+
+	return notice{Text: "Copyright (c) " + strings.Join(names, ", "), Source: "pom.xml developers"}
+}
+
+// mavenLicenseFiles returns the META-INF license files of the artifact's JAR.
+func mavenLicenseFiles(repo mavenRepository, purl string) []licenseFile {
+	licenses, _ := readMavenJARFiles(repo.file(parseMavenPURL(purl), "jar"))
+
+	return licenses
+}
+
+// mavenNoticeFiles returns the META-INF NOTICE files of the JAR.
+func mavenNoticeFiles(repo mavenRepository, purl string) []licenseFile {
+	_, notices := readMavenJARFiles(repo.file(parseMavenPURL(purl), "jar"))
+
+	return notices
+}
+
+// mavenDeclaredLicenses returns the license names declared in the POM (or its
+// parents), falling back to the license URL when a name is missing. A URL is
+// only resolved through the license map.
+func mavenDeclaredLicenses(repo mavenRepository, purl string) []string {
+	pom, ok := readMavenPOM(repo, parseMavenPURL(purl))
+	if !ok {
+		return nil
+	}
+
+	var licenses []string
+
+	for _, l := range pom.Licenses {
+		if name := strings.TrimSpace(firstNonEmpty(l.Name, func() string { return l.URL })); name != "" {
+			licenses = append(licenses, name)
+		}
+	}
+
+	return licenses
+}
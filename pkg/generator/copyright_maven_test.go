@@ -0,0 +1,169 @@
+package generator
+
+import (
+	"archive/zip"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeMavenFile(t *testing.T, dir string, coords mavenCoordinates, ext, content string) {
+	t.Helper()
+
+	groupDir := filepath.FromSlash(strings.ReplaceAll(coords.GroupID, ".", "/"))
+	p := filepath.Join(dir, groupDir, coords.ArtifactID, coords.Version, coords.ArtifactID+"-"+coords.Version+"."+ext)
+	require.NoError(t, os.MkdirAll(filepath.Dir(p), 0o755))
+	require.NoError(t, os.WriteFile(p, []byte(content), 0o644))
+}
+
+func writeMavenJAR(t *testing.T, path string, files map[string]string) {
+	t.Helper()
+
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+
+	f, err := os.Create(path)
+	require.NoError(t, err)
+
+	w := zip.NewWriter(f)
+
+	for name, content := range files {
+		fw, err := w.Create(name)
+		require.NoError(t, err)
+
+		_, err = fw.Write([]byte(content))
+		require.NoError(t, err)
+	}
+
+	require.NoError(t, w.Close())
+	require.NoError(t, f.Close())
+}
+
+func TestParseMavenPURL(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t,
+		mavenCoordinates{GroupID: "org.apache.commons", ArtifactID: "commons-lang3", Version: "3.14.0"},
+		parseMavenPURL("pkg:maven/org.apache.commons/commons-lang3@3.14.0?type=jar"))
+	assert.Equal(t, mavenCoordinates{}, parseMavenPURL("pkg:maven/commons-lang3@3.14.0"))
+}
+
+func TestExtractMavenCopyright_JARNotice(t *testing.T) {
+	t.Parallel()
+
+	m2 := t.TempDir()
+	jar := filepath.Join(m2, "org", "apache", "commons", "commons-lang3", "3.14.0", "commons-lang3-3.14.0.jar")
+	writeMavenJAR(t, jar, map[string]string{
+		"META-INF/LICENSE.txt":          "Apache License\nVersion 2.0, January 2004",
+		"META-INF/NOTICE.txt":           "Apache Commons Lang\nCopyright 2001-2023 The Apache Software Foundation",
+		"org/apache/commons/Foo.class":  "binary",
+		"META-INF/maven/pom.properties": "version=3.14.0",
+	})
+
+	repo := mavenRepository{m2Dir: m2}
+	purl := "pkg:maven/org.apache.commons/commons-lang3@3.14.0"
+
+	assert.Equal(t, notice{Text: "Copyright 2001-2023 The Apache Software Foundation", Source: "META-INF/NOTICE.txt"}, extractMavenCopyright(repo, purl))
+	assert.Equal(t, []licenseFile{{Name: "META-INF/LICENSE.txt", Text: "Apache License\nVersion 2.0, January 2004"}}, mavenLicenseFiles(repo, purl))
+	assert.Equal(t,
+		[]licenseFile{{Name: "META-INF/NOTICE.txt", Text: "Apache Commons Lang\nCopyright 2001-2023 The Apache Software Foundation"}},
+		copyrightEnricher{mavenRepository: repo}.noticeFiles(purl))
+}
+
+func TestExtractMavenCopyright_ParentPOM(t *testing.T) {
+	t.Parallel()
+
+	m2 := t.TempDir()
+
+	parent := mavenCoordinates{GroupID: "com.example", ArtifactID: "parent", Version: "1"}
+	writeMavenFile(t, m2, parent, "pom", `<project>
+  <organization><name>Example Inc.</name></organization>
+  <licenses><license><name>The Apache Software License, Version 2.0</name></license></licenses>
+</project>`)
+
+	child := mavenCoordinates{GroupID: "com.example", ArtifactID: "child", Version: "2.0"}
+	writeMavenFile(t, m2, child, "pom", `<project>
+  <parent><groupId>com.example</groupId><artifactId>parent</artifactId><version>1</version></parent>
+  <artifactId>child</artifactId>
+  <developers><developer><name>Jane Doe</name></developer></developers>
+</project>`)
+
+	repo := mavenRepository{m2Dir: m2}
+	purl := "pkg:maven/com.example/child@2.0"
+
+	assert.Equal(t, notice{Text: "Copyright (c) Example Inc.", Source: "pom.xml organization"}, extractMavenCopyright(repo, purl))
+	assert.Equal(t, []string{"The Apache Software License, Version 2.0"}, mavenDeclaredLicenses(repo, purl))
+}
+
+func TestExtractMavenCopyright_Developers(t *testing.T) {
+	t.Parallel()
+
+	m2 := t.TempDir()
+	writeMavenFile(t, m2, mavenCoordinates{GroupID: "io.example", ArtifactID: "lib", Version: "1.0"}, "pom", `<project>
+  <developers>
+    <developer><name>Jane Doe</name></developer>
+    <developer><organization>Acme Corp</organization></developer>
+  </developers>
+</project>`)
+
+	got := extractMavenCopyright(mavenRepository{m2Dir: m2}, "pkg:maven/io.example/lib@1.0")
+	assert.Equal(t, notice{Text: "Copyright (c) Jane Doe, Acme Corp", Source: "pom.xml developers"}, got)
+}
+
+func TestMavenRepository_GradleCache(t *testing.T) {
+	t.Parallel()
+
+	gradle := t.TempDir()
+	p := filepath.Join(gradle, "com.google.guava", "guava", "33.0.0-jre", "a5c6e9f0a1b2", "guava-33.0.0-jre.pom")
+	require.NoError(t, os.MkdirAll(filepath.Dir(p), 0o755))
+	require.NoError(t, os.WriteFile(p, []byte("<project/>"), 0o644))
+
+	repo := mavenRepository{m2Dir: t.TempDir(), gradleDir: gradle}
+	coords := mavenCoordinates{GroupID: "com.google.guava", ArtifactID: "guava", Version: "33.0.0-jre"}
+
+	assert.Equal(t, p, repo.file(coords, "pom"))
+	assert.Empty(t, repo.file(coords, "jar"))
+}
+
+func TestBuildIndex_MavenDeclaredLicense(t *testing.T) {
+	t.Parallel()
+
+	m2 := t.TempDir()
+	writeMavenFile(t, m2, mavenCoordinates{GroupID: "io.example", ArtifactID: "lib", Version: "1.0"}, "pom", `<project>
+  <licenses>
+    <license><name>The Apache Software License, Version 2.0</name></license>
+    <license><name>MIT</name></license>
+  </licenses>
+</project>`)
+
+	licenseMap := map[string]string{"The Apache Software License, Version 2.0": "Apache-2.0"}
+	components := []Component{{Name: "lib", Version: "1.0", PURL: "pkg:maven/io.example/lib@1.0"}}
+
+	_, byKey := buildIndex(components, inputs{licenseMap: licenseMap}, copyrightEnricher{mavenRepository: mavenRepository{m2Dir: m2}}, nil)
+
+	assert.Equal(t, []string{"Apache-2.0", "MIT"}, byKey["pkg:maven/io.example/lib@1.0"].LicenseIDs)
+	assert.Equal(t, licenseSourceMetadata, byKey["pkg:maven/io.example/lib@1.0"].LicenseSource)
+}
+
+func TestBuildIndex_MavenDeclaredLicenseURL(t *testing.T) {
+	t.Parallel()
+
+	m2 := t.TempDir()
+	writeMavenFile(t, m2, mavenCoordinates{GroupID: "io.example", ArtifactID: "lib", Version: "1.0"}, "pom", `<project>
+  <licenses>
+    <license><url>https://www.apache.org/licenses/LICENSE-2.0.txt</url></license>
+    <license><url>https://example.com/license.html</url></license>
+  </licenses>
+</project>`)
+
+	licenseMap := map[string]string{"https://www.apache.org/licenses/LICENSE-2.0.txt": "Apache-2.0"}
+	components := []Component{{Name: "lib", Version: "1.0", PURL: "pkg:maven/io.example/lib@1.0"}}
+
+	_, byKey := buildIndex(components, inputs{licenseMap: licenseMap}, copyrightEnricher{mavenRepository: mavenRepository{m2Dir: m2}}, nil)
+
+	assert.Equal(t, []string{"Apache-2.0"}, byKey["pkg:maven/io.example/lib@1.0"].LicenseIDs)
+	assert.Equal(t, "Apache-2.0", byKey["pkg:maven/io.example/lib@1.0"].LicenseExpression)
+}
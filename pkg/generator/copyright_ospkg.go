@@ -0,0 +1,511 @@
+package generator
+
+import (
+	"bufio"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	rpmdb "github.com/knqyf263/go-rpmdb/pkg"
+	"github.com/rs/zerolog/log"
+)
+
+// rpmDBPaths lists the locations of the rpm database in a root filesystem,
+// covering the SQLite (RHEL 9+, Fedora 33+), NDB (SUSE) and Berkeley DB
+// (older releases) backends.
+var rpmDBPaths = []string{
+	"usr/lib/sysimage/rpm/rpmdb.sqlite",
+	"usr/lib/sysimage/rpm/Packages.db",
+	"var/lib/rpm/rpmdb.sqlite",
+	"var/lib/rpm/Packages.db",
+	"var/lib/rpm/Packages",
+}
+
+// osPackage is a distro package installed in a root filesystem.
+type osPackage struct {
+	Name    string
+	Origin  string
+	License string
+}
+
+// rootFS gives access to the distro packages installed in a root filesystem
+// (e.g. an extracted container image).
+type rootFS struct {
+	dir string
+	apk map[string]osPackage
+	rpm map[string]osPackage
+}
+
+// loadRootFS indexes the apk and rpm databases of the root filesystem at dir.
+// dpkg packages need no index: their copyright files are read directly.
+func loadRootFS(dir string) rootFS {
+	if dir == "" {
+		return rootFS{}
+	}
+
+	fs := rootFS{dir: dir}
+
+	if f, err := os.Open(fs.path("lib/apk/db/installed")); err == nil {
+		fs.apk = parseApkInstalled(f)
+		_ = f.Close()
+	}
+
+	for _, p := range rpmDBPaths {
+		p = fs.path(p)
+		if !isFile(p) {
+			continue
+		}
+
+		pkgs, err := readRpmDB(p)
+		if err != nil {
+			log.Warn().Err(err).Str("path", p).Msg("Failed to read the rpm database.")
+
+			continue
+		}
+
+		fs.rpm = pkgs
+
+		break
+	}
+
+	return fs
+}
+
+// maxRootFSLinks bounds the symbolic links followed to resolve a path of a root
+// filesystem, like the kernel does, so that link loops fail.
+const maxRootFSLinks = 40
+
+// path returns the host path of p, a slash-separated path of the root
+// filesystem. Symbolic links are resolved as if the root filesystem were "/":
+// absolute targets and ".." never leave it, so the files of the host are never
+// read. It returns an empty string when a link cannot be resolved.
+func (fs rootFS) path(p string) string {
+	var (
+		resolved string
+		links    int
+	)
+
+	pending := strings.Split(p, "/")
+
+	for len(pending) > 0 {
+		name := pending[0]
+		pending = pending[1:]
+
+		switch name {
+		case "", ".":
+			continue
+		case "..":
+			if resolved = path.Dir(resolved); resolved == "." {
+				resolved = ""
+			}
+
+			continue
+		}
+
+		next := path.Join(resolved, name)
+
+		host := filepath.Join(fs.dir, filepath.FromSlash(next))
+
+		info, err := os.Lstat(host)
+		if err != nil || info.Mode()&os.ModeSymlink == 0 {
+			resolved = next
+
+			continue
+		}
+
+		target, err := os.Readlink(host)
+		if err != nil || links == maxRootFSLinks {
+			return ""
+		}
+
+		links++
+
+		target = filepath.ToSlash(target)
+		if strings.HasPrefix(target, "/") {
+			resolved = ""
+		}
+
+		pending = append(strings.Split(target, "/"), pending...)
+	}
+
+	return filepath.Join(fs.dir, filepath.FromSlash(resolved))
+}
+
+// parseApkInstalled parses Alpine's lib/apk/db/installed: one record per
+// package, separated by blank lines, with single-letter keys.
+func parseApkInstalled(r io.Reader) map[string]osPackage {
+	pkgs := map[string]osPackage{}
+
+	var current osPackage
+
+	flush := func() {
+		if current.Name != "" {
+			pkgs[current.Name] = current
+		}
+
+		current = osPackage{}
+	}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" {
+			flush()
+
+			continue
+		}
+
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+
+		switch key {
+		case "P":
+			current.Name = value
+		case "o":
+			current.Origin = value
+		case "L":
+			current.License = value
+		}
+	}
+
+	flush()
+
+	return pkgs
+}
+
+func readRpmDB(path string) (map[string]osPackage, error) {
+	db, err := rpmdb.Open(path)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() { _ = db.Close() }()
+
+	list, err := db.ListPackages()
+	if err != nil {
+		return nil, err
+	}
+
+	pkgs := make(map[string]osPackage, len(list))
+	for _, p := range list {
+		pkgs[p.Name] = osPackage{Name: p.Name, License: p.License}
+	}
+
+	return pkgs, nil
+}
+
+// parseOSPackagePURL returns the type ("deb", "apk" or "rpm") and package name
+// of a distro PURL such as "pkg:deb/debian/curl@7.88.1-10?arch=amd64".
+func parseOSPackagePURL(purl string) (string, string) {
+	p, ok := parsePURL(purl)
+	if !ok || !slices.Contains([]string{"deb", "apk", "rpm"}, p.Type) {
+		return "", ""
+	}
+
+	return p.Type, p.Name
+}
+
+// docDirs returns the directories that may hold the license files of a package,
+// relative to the root filesystem.
+func (fs rootFS) docDirs(typ, name string) []string {
+	names := []string{name}
+	if p, ok := fs.apk[name]; typ == "apk" && ok && p.Origin != "" && p.Origin != name {
+		names = append(names, p.Origin)
+	}
+
+	var dirs []string
+
+	for _, n := range names {
+		dirs = append(dirs, "usr/share/licenses/"+n, "usr/share/doc/"+n)
+	}
+
+	return dirs
+}
+
+// licenseFilePaths returns the license files of a distro package: the dpkg
+// copyright file, or the files under /usr/share/licenses/<name>/ and the
+// license files of /usr/share/doc/<name>/.
+func (fs rootFS) licenseFilePaths(purl string) []string {
+	typ, name := parseOSPackagePURL(purl)
+	if fs.dir == "" || name == "" {
+		return nil
+	}
+
+	if typ == "deb" {
+		p := fs.debCopyrightPath(name)
+		if !isFile(p) {
+			return nil
+		}
+
+		return []string{p}
+	}
+
+	var paths []string
+
+	for _, dir := range fs.docDirs(typ, name) {
+		hostDir := fs.path(dir)
+
+		var names []string
+
+		if path.Base(path.Dir(dir)) == "licenses" {
+			entries, err := os.ReadDir(hostDir)
+			if err != nil {
+				continue
+			}
+
+			for _, entry := range entries {
+				names = append(names, entry.Name())
+			}
+		} else {
+			for _, p := range findCaseInsensitiveFiles(hostDir, licenseFileNames) {
+				names = append(names, filepath.Base(p))
+			}
+		}
+
+		// The files can be links too.
+		for _, n := range names {
+			if p := fs.path(path.Join(dir, n)); isFile(p) {
+				paths = append(paths, p)
+			}
+		}
+	}
+
+	return paths
+}
+
+// extractOSPackageCopyright reads the copyright notice of a distro package.
+// Machine-readable (DEP-5) dpkg copyright files yield the holders of every
+// Files stanza; other files yield their first copyright line.
+func extractOSPackageCopyright(fs rootFS, purl string) notice {
+	typ, name := parseOSPackagePURL(purl)
+	if fs.dir == "" || name == "" {
+		return notice{}
+	}
+
+	if typ == "deb" {
+		text := readFileText(fs.debCopyrightPath(name))
+
+		if stanzas := parseDEP5(text); stanzas != nil {
+			if holders := dep5Copyrights(stanzas); len(holders) > 0 {
+				return notice{Text: strings.Join(holders, "\n"), Source: "debian/copyright (DEP-5)"}
+			}
+
+			return notice{}
+		}
+
+		if c := firstCopyrightLine(text); c != "" {
+			return notice{Text: c, Source: "debian/copyright"}
+		}
+
+		return notice{}
+	}
+
+	for _, p := range fs.licenseFilePaths(purl) {
+		if n := fileNotice(p); n.Text != "" {
+			return n
+		}
+	}
+
+	return notice{}
+}
+
+// osPackageDeclaredLicense returns the license declared by the package manager:
+// the License field of DEP-5 stanzas, the L: field of the apk database, or the
+// License header of the rpm database.
+func osPackageDeclaredLicense(fs rootFS, purl string) string {
+	typ, name := parseOSPackagePURL(purl)
+	if fs.dir == "" || name == "" {
+		return ""
+	}
+
+	switch typ {
+	case "deb":
+		return dep5License(parseDEP5(readFileText(fs.debCopyrightPath(name))))
+	case "apk":
+		return fs.apk[name].License
+	case "rpm":
+		return fs.rpm[name].License
+	}
+
+	return ""
+}
+
+func (fs rootFS) debCopyrightPath(name string) string {
+	return fs.path("usr/share/doc/" + name + "/copyright")
+}
+
+// ─── DEP-5 ───────────────────────────────────────────────────────────────────
+
+// dep5Stanza is a paragraph of a machine-readable debian/copyright file. Keys
+// are lowercased; continuation lines are joined with "\n".
+type dep5Stanza map[string]string
+
+// parseDEP5 parses a machine-readable debian/copyright file
+// (https://www.debian.org/doc/packaging-manuals/copyright-format/1.0/). It
+// returns nil when text is not in that format.
+func parseDEP5(text string) []dep5Stanza {
+	var (
+		stanzas []dep5Stanza
+		current dep5Stanza
+		lastKey string
+	)
+
+	for line := range strings.SplitSeq(strings.ReplaceAll(text, "\r\n", "\n"), "\n") {
+		if strings.TrimSpace(line) == "" {
+			if current != nil {
+				stanzas = append(stanzas, current)
+				current = nil
+			}
+
+			continue
+		}
+
+		if line[0] == ' ' || line[0] == '\t' {
+			if current != nil && lastKey != "" {
+				value := strings.TrimSpace(line)
+				if value == "." {
+					value = ""
+				}
+
+				current[lastKey] += "\n" + value
+			}
+
+			continue
+		}
+
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+
+		if current == nil {
+			current = dep5Stanza{}
+		}
+
+		lastKey = strings.ToLower(strings.TrimSpace(key))
+		current[lastKey] = strings.TrimSpace(value)
+	}
+
+	if current != nil {
+		stanzas = append(stanzas, current)
+	}
+
+	if len(stanzas) == 0 || !strings.Contains(stanzas[0]["format"], "copyright-format") && !strings.Contains(stanzas[0]["format"], "dep5") {
+		return nil
+	}
+
+	return stanzas
+}
+
+// dep5Copyrights returns the copyright holders of every Files stanza, in
+// order and without duplicates, each prefixed with "Copyright" when missing.
+func dep5Copyrights(stanzas []dep5Stanza) []string {
+	var holders []string
+
+	for _, stanza := range stanzas {
+		if _, ok := stanza["files"]; !ok {
+			continue
+		}
+
+		for line := range strings.SplitSeq(stanza["copyright"], "\n") {
+			line = strings.TrimSpace(line)
+			if line == "" {
+				continue
+			}
+
+			lower := strings.ToLower(line)
+			if !strings.HasPrefix(lower, "copyright") && !strings.HasPrefix(lower, "(c)") && !strings.HasPrefix(line, "©") {
+				line = "Copyright " + line
+			}
+
+			if !slices.Contains(holders, line) {
+				holders = append(holders, line)
+			}
+		}
+	}
+
+	return holders
+}
+
+// dep5License combines the licenses of every Files stanza into an SPDX
+// expression: a package is distributed under all of its per-file licenses.
+func dep5License(stanzas []dep5Stanza) string {
+	var exprs []string
+
+	for _, stanza := range stanzas {
+		if _, ok := stanza["files"]; !ok {
+			continue
+		}
+
+		// The first line of License is the short name; the rest is its text.
+		short, _, _ := strings.Cut(stanza["license"], "\n")
+
+		expr := debianLicenseExpression(short)
+		if expr != "" && !slices.Contains(exprs, expr) {
+			exprs = append(exprs, expr)
+		}
+	}
+
+	if len(exprs) == 1 {
+		return exprs[0]
+	}
+
+	for i, expr := range exprs {
+		if strings.Contains(expr, " ") {
+			exprs[i] = "(" + expr + ")"
+		}
+	}
+
+	return strings.Join(exprs, " AND ")
+}
+
+// debianLicenseIDs maps the DEP-5 standard short names that differ from their
+// SPDX identifiers.
+var debianLicenseIDs = map[string]string{
+	"apache-2.0":   "Apache-2.0",
+	"artistic":     "Artistic-1.0",
+	"artistic-2.0": "Artistic-2.0",
+	"expat":        "MIT",
+	"gpl-1":        "GPL-1.0-only",
+	"gpl-1+":       "GPL-1.0-or-later",
+	"gpl-2":        "GPL-2.0-only",
+	"gpl-2+":       "GPL-2.0-or-later",
+	"gpl-3":        "GPL-3.0-only",
+	"gpl-3+":       "GPL-3.0-or-later",
+	"lgpl-2":       "LGPL-2.0-only",
+	"lgpl-2+":      "LGPL-2.0-or-later",
+	"lgpl-2.1":     "LGPL-2.1-only",
+	"lgpl-2.1+":    "LGPL-2.1-or-later",
+	"lgpl-3":       "LGPL-3.0-only",
+	"lgpl-3+":      "LGPL-3.0-or-later",
+	"mpl-1.1":      "MPL-1.1",
+	"mpl-2.0":      "MPL-2.0",
+	"zlib":         "Zlib",
+}
+
+// debianLicenseExpression turns a DEP-5 license short name expression (e.g.
+// "GPL-2+ or Artistic") into an SPDX one ("GPL-2.0-or-later OR Artistic-1.0").
+// Unknown names are kept as-is for the license map to resolve.
+func debianLicenseExpression(short string) string {
+	fields := strings.Fields(strings.ReplaceAll(strings.ReplaceAll(short, ",", " "), "|", " or "))
+
+	for i, field := range fields {
+		switch lower := strings.ToLower(field); lower {
+		case "and", "or", "with":
+			fields[i] = strings.ToUpper(lower)
+		default:
+			if id, ok := debianLicenseIDs[lower]; ok {
+				fields[i] = id
+			}
+		}
+	}
+
+	return strings.Join(fields, " ")
+}
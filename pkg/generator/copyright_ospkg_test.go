@@ -0,0 +1,189 @@
+package generator
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const curlDEP5 = `Format: https://www.debian.org/doc/packaging-manuals/copyright-format/1.0/
+Upstream-Name: curl
+Source: https://curl.se/
+
+Files: *
+Copyright: 1996-2023, Daniel Stenberg <daniel@haxx.se>
+ Copyright (c) 1998-2023 Daniel Stenberg
+License: curl
+
+Files: lib/krb5.c
+Copyright: 1995-2003, Kungliga Tekniska Högskolan
+License: BSD-3-clause
+
+Files: debian/*
+Copyright: 2013, Alessandro Ghedini <ghedo@debian.org>
+License: GPL-2+
+ This program is free software.
+ .
+ See /usr/share/common-licenses/GPL-2.
+
+License: curl
+ Permission to use, copy, modify, and distribute this software...
+`
+
+func TestParseOSPackagePURL(t *testing.T) {
+	t.Parallel()
+
+	typ, name := parseOSPackagePURL("pkg:deb/debian/curl@7.88.1-10+deb12u5?arch=amd64&distro=debian-12")
+	assert.Equal(t, "deb", typ)
+	assert.Equal(t, "curl", name)
+
+	typ, name = parseOSPackagePURL("pkg:apk/alpine/busybox-binsh@1.36.1-r15?arch=x86_64")
+	assert.Equal(t, "apk", typ)
+	assert.Equal(t, "busybox-binsh", name)
+
+	typ, name = parseOSPackagePURL("pkg:rpm/redhat/openssl-libs@3.0.7-27.el9?epoch=1")
+	assert.Equal(t, "rpm", typ)
+	assert.Equal(t, "openssl-libs", name)
+
+	typ, _ = parseOSPackagePURL("pkg:npm/curl@1.0.0")
+	assert.Empty(t, typ)
+}
+
+func TestExtractOSPackageCopyright_DebDEP5(t *testing.T) {
+	t.Parallel()
+
+	rootfs := t.TempDir()
+	writeTestFile(t, rootfs, "usr/share/doc/curl/copyright", curlDEP5)
+
+	fs := loadRootFS(rootfs)
+
+	got := extractOSPackageCopyright(fs, "pkg:deb/debian/curl@7.88.1-10?arch=amd64")
+	assert.Equal(t, notice{
+		Text: strings.Join([]string{
+			"Copyright 1996-2023, Daniel Stenberg <daniel@haxx.se>",
+			"Copyright (c) 1998-2023 Daniel Stenberg",
+			"Copyright 1995-2003, Kungliga Tekniska Högskolan",
+			"Copyright 2013, Alessandro Ghedini <ghedo@debian.org>",
+		}, "\n"),
+		Source: "debian/copyright (DEP-5)",
+	}, got)
+
+	assert.Equal(t, "curl AND BSD-3-clause AND GPL-2.0-or-later", osPackageDeclaredLicense(fs, "pkg:deb/debian/curl@7.88.1-10"))
+
+	files := readLicenseFiles(fs.licenseFilePaths("pkg:deb/debian/curl@7.88.1-10"))
+	require.Len(t, files, 1)
+	assert.Equal(t, "copyright", files[0].Name)
+}
+
+func TestExtractOSPackageCopyright_DebFreeForm(t *testing.T) {
+	t.Parallel()
+
+	rootfs := t.TempDir()
+	writeTestFile(t, rootfs, "usr/share/doc/zlib1g/copyright",
+		"This is Debian's prepackaged version of zlib.\n\nCopyright (C) 1995-2013 Jean-loup Gailly and Mark Adler\n")
+
+	fs := loadRootFS(rootfs)
+
+	got := extractOSPackageCopyright(fs, "pkg:deb/debian/zlib1g@1:1.2.13")
+	assert.Equal(t, notice{Text: "Copyright (C) 1995-2013 Jean-loup Gailly and Mark Adler", Source: "debian/copyright"}, got)
+	assert.Empty(t, osPackageDeclaredLicense(fs, "pkg:deb/debian/zlib1g@1:1.2.13"))
+}
+
+func TestExtractOSPackageCopyright_Apk(t *testing.T) {
+	t.Parallel()
+
+	rootfs := t.TempDir()
+	writeTestFile(t, rootfs, "lib/apk/db/installed",
+		"C:Q1abc=\nP:busybox\nV:1.36.1-r15\nL:GPL-2.0-only\no:busybox\n\n"+
+			"C:Q1def=\nP:busybox-binsh\nV:1.36.1-r15\nL:GPL-2.0-only\no:busybox\n")
+	writeTestFile(t, rootfs, "usr/share/licenses/busybox/COPYING", "Copyright (C) 1998-2011 Erik Andersen, Rob Landley, Denys Vlasenko\n")
+
+	fs := loadRootFS(rootfs)
+
+	assert.Equal(t, "GPL-2.0-only", osPackageDeclaredLicense(fs, "pkg:apk/alpine/busybox-binsh@1.36.1-r15"))
+
+	// busybox-binsh ships no license files itself: they come from its origin.
+	got := extractOSPackageCopyright(fs, "pkg:apk/alpine/busybox-binsh@1.36.1-r15")
+	assert.Equal(t, notice{Text: "Copyright (C) 1998-2011 Erik Andersen, Rob Landley, Denys Vlasenko", Source: "COPYING"}, got)
+}
+
+func TestExtractOSPackageCopyright_Rpm(t *testing.T) {
+	t.Parallel()
+
+	rootfs := t.TempDir()
+	writeTestFile(t, rootfs, "usr/share/licenses/openssl-libs/LICENSE.txt", "Copyright 1995-2023 The OpenSSL Project Authors. All Rights Reserved.\n")
+
+	fs := rootFS{dir: rootfs, rpm: map[string]osPackage{"openssl-libs": {Name: "openssl-libs", License: "ASL 2.0"}}}
+
+	assert.Equal(t, "ASL 2.0", osPackageDeclaredLicense(fs, "pkg:rpm/redhat/openssl-libs@3.0.7-27.el9"))
+	assert.Equal(t,
+		notice{Text: "Copyright 1995-2023 The OpenSSL Project Authors. All Rights Reserved.", Source: "LICENSE.txt"},
+		extractOSPackageCopyright(fs, "pkg:rpm/redhat/openssl-libs@3.0.7-27.el9"))
+}
+
+func TestRootFSPath_Symlinks(t *testing.T) {
+	t.Parallel()
+
+	host := t.TempDir()
+	writeTestFile(t, host, "copyright", "Copyright 2024 Host\n")
+
+	rootfs := t.TempDir()
+	writeTestFile(t, rootfs, "usr/share/doc/curl/copyright", "Copyright 2023 Daniel Stenberg\n")
+	require.NoError(t, os.MkdirAll(filepath.Join(rootfs, "usr", "share", "doc", "escape"), 0o755))
+	require.NoError(t, os.MkdirAll(filepath.Join(rootfs, "usr", "share", "licenses", "escape"), 0o755))
+
+	// Absolute and ".." targets are resolved inside the root filesystem.
+	require.NoError(t, os.Symlink("/usr/share/doc/curl", filepath.Join(rootfs, "usr", "share", "doc", "libcurl4")))
+	require.NoError(t, os.Symlink(filepath.Join(host, "copyright"), filepath.Join(rootfs, "usr", "share", "doc", "escape", "copyright")))
+	require.NoError(t, os.Symlink(strings.Repeat("../", 20)+filepath.Join(host, "copyright"),
+		filepath.Join(rootfs, "usr", "share", "licenses", "escape", "COPYING")))
+	require.NoError(t, os.Symlink("loop", filepath.Join(rootfs, "loop")))
+
+	fs := rootFS{dir: rootfs}
+
+	assert.Equal(t, filepath.Join(rootfs, "usr", "share", "doc", "curl", "copyright"), fs.path("usr/share/doc/libcurl4/copyright"))
+	assert.Equal(t, filepath.Join(rootfs, host, "copyright"), fs.path("usr/share/doc/escape/copyright"))
+	assert.Equal(t, filepath.Join(rootfs, "etc"), fs.path("../../etc"))
+	assert.Empty(t, fs.path("loop/copyright"))
+
+	assert.Equal(t, notice{Text: "Copyright 2023 Daniel Stenberg", Source: "debian/copyright"},
+		extractOSPackageCopyright(fs, "pkg:deb/debian/libcurl4@7.88.1-10"))
+	assert.Empty(t, fs.licenseFilePaths("pkg:deb/debian/escape@1.0.0"))
+	assert.Empty(t, fs.licenseFilePaths("pkg:apk/alpine/escape@1.0.0"))
+}
+
+func TestExtractOSPackageCopyright_NoRootFS(t *testing.T) {
+	t.Parallel()
+
+	assert.Empty(t, extractOSPackageCopyright(rootFS{}, "pkg:deb/debian/curl@7.88.1-10"))
+	assert.Empty(t, osPackageDeclaredLicense(rootFS{}, "pkg:deb/debian/curl@7.88.1-10"))
+}
+
+func TestDebianLicenseExpression(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "GPL-1.0-or-later OR Artistic-1.0", debianLicenseExpression("GPL-1+ or Artistic"))
+	assert.Equal(t, "MIT", debianLicenseExpression("Expat"))
+	assert.Equal(t, "LGPL-2.1-or-later", debianLicenseExpression("LGPL-2.1+"))
+	assert.Equal(t, "public-domain", debianLicenseExpression("public-domain"))
+}
+
+func TestBuildIndex_DebDeclaredLicense(t *testing.T) {
+	t.Parallel()
+
+	rootfs := t.TempDir()
+	writeTestFile(t, rootfs, "usr/share/doc/curl/copyright", curlDEP5)
+
+	licenseMap := map[string]string{"curl": "curl"}
+	components := []Component{{Name: "curl", Version: "7.88.1-10", PURL: "pkg:deb/debian/curl@7.88.1-10"}}
+
+	_, byKey := buildIndex(components, inputs{licenseMap: licenseMap}, copyrightEnricher{rootFS: loadRootFS(rootfs)}, nil)
+
+	assert.Equal(t, []string{"BSD-3-Clause", "GPL-2.0-or-later", "curl"}, byKey["pkg:deb/debian/curl@7.88.1-10"].LicenseIDs)
+	assert.Equal(t, licenseSourceMetadata, byKey["pkg:deb/debian/curl@7.88.1-10"].LicenseSource)
+	assert.Equal(t, "debian/copyright (DEP-5)", byKey["pkg:deb/debian/curl@7.88.1-10"].CopyrightSource)
+}
@@ -50,7 +50,7 @@ func TestExtractGoCopyrightFromCache(t *testing.T) {
 	require.NoError(t, os.WriteFile(filepath.Join(modDir, "LICENSE"), []byte("Copyright 2009 The Go Authors.\n\nBSD-3-Clause..."), 0o644))
 
 	got := extractGoCopyrightFromCache(dir, "pkg:golang/golang.org/x/sync@v0.19.0?goarch=arm64&goos=darwin&type=module")
-	assert.Equal(t, "Copyright 2009 The Go Authors.", got)
+	assert.Equal(t, "Copyright 2009 The Go Authors.", got.Text)
 }
 
 func TestExtractGoCopyrightFromCache_EscapedPath(t *testing.T) {
@@ -62,7 +62,7 @@ func TestExtractGoCopyrightFromCache_EscapedPath(t *testing.T) {
 	require.NoError(t, os.WriteFile(filepath.Join(modDir, "LICENSE"), []byte("The MIT License (MIT)\n\nCopyright (c) 2013 TOML Authors"), 0o644))
 
 	got := extractGoCopyrightFromCache(dir, "pkg:golang/github.com/BurntSushi/toml@v1.3.2")
-	assert.Equal(t, "Copyright (c) 2013 TOML Authors", got)
+	assert.Equal(t, "Copyright (c) 2013 TOML Authors", got.Text)
 }
 
 func TestExtractGoCopyrightFromCache_NotFound(t *testing.T) {
@@ -71,6 +71,34 @@ func TestExtractGoCopyrightFromCache_NotFound(t *testing.T) {
 	assert.Empty(t, extractGoCopyrightFromCache("/nonexistent/path", "pkg:golang/golang.org/x/sync@v0.19.0"))
 }
 
+func TestLicenseFiles_GoModule(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	modDir := filepath.Join(dir, "github.com", "foo", "bar@v1.0.0")
+	require.NoError(t, os.MkdirAll(modDir, 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(modDir, "LICENSE"), []byte("MIT License"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(modDir, "COPYING"), []byte("Apache License"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(modDir, "README.md"), []byte("# bar"), 0o644))
+
+	got := copyrightEnricher{gomodcache: dir}.licenseFiles("pkg:golang/github.com/foo/bar@v1.0.0")
+	assert.Equal(t, []licenseFile{{Name: "LICENSE", Text: "MIT License"}, {Name: "COPYING", Text: "Apache License"}}, got)
+}
+
+func TestNoticeFiles_GoModule(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	modDir := filepath.Join(dir, "github.com", "foo", "bar@v1.0.0")
+	require.NoError(t, os.MkdirAll(modDir, 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(modDir, "LICENSE"), []byte("Apache License"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(modDir, "notice.txt"), []byte("Bar\nCopyright 2024 Foo"), 0o644))
+
+	enricher := copyrightEnricher{gomodcache: dir}
+	assert.Equal(t, []licenseFile{{Name: "notice.txt", Text: "Bar\nCopyright 2024 Foo"}}, enricher.noticeFiles("pkg:golang/github.com/foo/bar@v1.0.0"))
+	assert.Empty(t, enricher.noticeFiles("pkg:golang/github.com/foo/baz@v1.0.0"))
+}
+
 // ─── npm ─────────────────────────────────────────────────────────────────────
 
 func TestParseNpmPURL(t *testing.T) {
@@ -100,7 +128,23 @@ func TestExtractNpmCopyright_LicenseFile(t *testing.T) {
 	require.NoError(t, os.WriteFile(filepath.Join(pkgDir, "LICENSE"), []byte("MIT License\n\nCopyright (c) 2012-2018 The Dojo Foundation <http://dojofoundation.org/>"), 0o644))
 
 	got := extractNpmCopyright(dir, "pkg:npm/lodash@4.17.21")
-	assert.Equal(t, "Copyright (c) 2012-2018 The Dojo Foundation <http://dojofoundation.org/>", got)
+	assert.Equal(t, "Copyright (c) 2012-2018 The Dojo Foundation <http://dojofoundation.org/>", got.Text)
+	assert.Equal(t, "LICENSE", got.Source)
+}
+
+func TestExtractNpmCopyright_LicenseFileVariants(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	pkgDir := filepath.Join(dir, "some-pkg")
+	require.NoError(t, os.MkdirAll(pkgDir, 0o755))
+	// LICENSE has no copyright line, so the next variant is read.
+	require.NoError(t, os.WriteFile(filepath.Join(pkgDir, "LICENSE"), []byte("See LICENSE-MIT."), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(pkgDir, "LICENSE-MIT"), []byte("MIT\n\nCopyright (c) 2020 Foo"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(pkgDir, "COPYING"), []byte("Copyright (c) 2020 Bar"), 0o644))
+
+	got := extractNpmCopyright(dir, "pkg:npm/some-pkg@1.0.0")
+	assert.Equal(t, notice{Text: "Copyright (c) 2020 Foo", Source: "LICENSE-MIT"}, got)
 }
 
 func TestExtractNpmCopyright_PackageJSONAuthorString(t *testing.T) {
@@ -120,7 +164,7 @@ func TestExtractNpmCopyright_PackageJSONAuthorString(t *testing.T) {
 	require.NoError(t, os.WriteFile(filepath.Join(pkgDir, "package.json"), data, 0o644))
 
 	got := extractNpmCopyright(dir, "pkg:npm/some-pkg@1.0.0")
-	assert.Equal(t, "Copyright (c) Jane Doe", got)
+	assert.Equal(t, "Copyright (c) Jane Doe", got.Text)
 }
 
 func TestExtractNpmCopyright_PackageJSONAuthorObject(t *testing.T) {
@@ -140,7 +184,50 @@ func TestExtractNpmCopyright_PackageJSONAuthorObject(t *testing.T) {
 	require.NoError(t, os.WriteFile(filepath.Join(pkgDir, "package.json"), data, 0o644))
 
 	got := extractNpmCopyright(dir, "pkg:npm/some-pkg@1.0.0")
-	assert.Equal(t, "Copyright (c) Acme Corp", got)
+	assert.Equal(t, "Copyright (c) Acme Corp", got.Text)
+}
+
+func TestExtractNpmCopyright_PackageJSONContributors(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	pkgDir := filepath.Join(dir, "some-pkg")
+	require.NoError(t, os.MkdirAll(pkgDir, 0o755))
+
+	pkg := map[string]any{
+		"author": "",
+		"contributors": []any{
+			"Jane Doe <jane@example.com>",
+			map[string]any{"name": "John Roe", "url": "https://john.dev"},
+		},
+		"maintainers": []any{"Ignored Maintainer"},
+	}
+
+	data, err := json.Marshal(pkg)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(pkgDir, "package.json"), data, 0o644))
+
+	got := extractNpmCopyright(dir, "pkg:npm/some-pkg@1.0.0")
+	assert.Equal(t, notice{Text: "Copyright (c) Jane Doe, John Roe", Source: "package.json contributors"}, got)
+}
+
+func TestExtractNpmCopyright_PackageJSONMaintainers(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	pkgDir := filepath.Join(dir, "some-pkg")
+	require.NoError(t, os.MkdirAll(pkgDir, 0o755))
+
+	pkg := map[string]any{
+		"maintainers": []any{map[string]any{"name": "Acme Corp", "email": "hi@acme.com"}},
+	}
+
+	data, err := json.Marshal(pkg)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(pkgDir, "package.json"), data, 0o644))
+
+	got := extractNpmCopyright(dir, "pkg:npm/some-pkg@1.0.0")
+	assert.Equal(t, notice{Text: "Copyright (c) Acme Corp", Source: "package.json maintainers"}, got)
 }
 
 func TestExtractNpmCopyright_ScopedPackage(t *testing.T) {
@@ -152,7 +239,7 @@ func TestExtractNpmCopyright_ScopedPackage(t *testing.T) {
 	require.NoError(t, os.WriteFile(filepath.Join(pkgDir, "LICENSE"), []byte("MIT\n\nCopyright (c) 2014-present Sebastian McKenzie"), 0o644))
 
 	got := extractNpmCopyright(dir, "pkg:npm/@babel/core@7.25.7")
-	assert.Equal(t, "Copyright (c) 2014-present Sebastian McKenzie", got)
+	assert.Equal(t, "Copyright (c) 2014-present Sebastian McKenzie", got.Text)
 }
 
 func TestExtractNpmCopyright_EmptyDir(t *testing.T) {
@@ -173,8 +260,22 @@ func TestExtractPythonCopyright(t *testing.T) {
 	metadata := "Metadata-Version: 2.1\nName: requests\nVersion: 2.28.0\nAuthor: Kenneth Reitz\nAuthor-email: me@kennethreitz.org\n"
 	require.NoError(t, os.WriteFile(filepath.Join(distInfo, "METADATA"), []byte(metadata), 0o644))
 
-	got := extractPythonCopyright(dir, "pkg:pypi/requests@2.28.0")
-	assert.Equal(t, "Copyright (c) Kenneth Reitz", got)
+	got := extractPythonCopyright([]string{dir}, "pkg:pypi/requests@2.28.0")
+	assert.Equal(t, "Copyright (c) Kenneth Reitz", got.Text)
+}
+
+func TestExtractPythonCopyright_MalformedMetadata(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	distInfo := filepath.Join(dir, "legacy-1.0.0.dist-info")
+	require.NoError(t, os.MkdirAll(distInfo, 0o755))
+
+	metadata := "Metadata-Version: 1.0\nName: legacy\nVersion: 1.0.0\nSummary: A legacy package\nwritten by hand\nAuthor: Jane\n  Doe\n\nBody: not a field\n"
+	require.NoError(t, os.WriteFile(filepath.Join(distInfo, "METADATA"), []byte(metadata), 0o644))
+
+	got := extractPythonCopyright([]string{dir}, "pkg:pypi/legacy@1.0.0")
+	assert.Equal(t, notice{Text: "Copyright (c) Jane Doe", Source: "METADATA Author"}, got)
 }
 
 func TestExtractPythonCopyright_HyphenToUnderscore(t *testing.T) {
@@ -188,8 +289,8 @@ func TestExtractPythonCopyright_HyphenToUnderscore(t *testing.T) {
 	metadata := "Metadata-Version: 2.1\nName: black-formatter\nVersion: 24.1.0\nAuthor: Łukasz Langa\n"
 	require.NoError(t, os.WriteFile(filepath.Join(distInfo, "METADATA"), []byte(metadata), 0o644))
 
-	got := extractPythonCopyright(dir, "pkg:pypi/black-formatter@24.1.0")
-	assert.Equal(t, "Copyright (c) Łukasz Langa", got)
+	got := extractPythonCopyright([]string{dir}, "pkg:pypi/black-formatter@24.1.0")
+	assert.Equal(t, "Copyright (c) Łukasz Langa", got.Text)
 }
 
 func TestExtractPythonCopyright_UnknownAuthor(t *testing.T) {
@@ -200,11 +301,166 @@ func TestExtractPythonCopyright_UnknownAuthor(t *testing.T) {
 	require.NoError(t, os.MkdirAll(distInfo, 0o755))
 	require.NoError(t, os.WriteFile(filepath.Join(distInfo, "METADATA"), []byte("Author: UNKNOWN\n"), 0o644))
 
-	assert.Empty(t, extractPythonCopyright(dir, "pkg:pypi/pkg@1.0.0"))
+	assert.Empty(t, extractPythonCopyright([]string{dir}, "pkg:pypi/pkg@1.0.0"))
+}
+
+func TestExtractPythonCopyright_PEP639LicenseFile(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	distInfo := filepath.Join(dir, "rich-13.7.0.dist-info")
+	require.NoError(t, os.MkdirAll(filepath.Join(distInfo, "licenses"), 0o755))
+
+	metadata := "Metadata-Version: 2.4\nName: rich\nVersion: 13.7.0\nAuthor-email: Will McGugan <will@example.com>\nLicense-Expression: MIT\nLicense-File: LICENSE\n\nDescription body.\n"
+	require.NoError(t, os.WriteFile(filepath.Join(distInfo, "METADATA"), []byte(metadata), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(distInfo, "licenses", "LICENSE"), []byte("Copyright (c) 2020 Will McGugan\n\nPermission..."), 0o644))
+
+	got := extractPythonCopyright([]string{dir}, "pkg:pypi/rich@13.7.0")
+	assert.Equal(t, notice{Text: "Copyright (c) 2020 Will McGugan", Source: "License-File LICENSE"}, got)
+}
+
+func TestExtractPythonCopyright_LegacyLicenseFileLocation(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	distInfo := filepath.Join(dir, "six-1.16.0.dist-info")
+	require.NoError(t, os.MkdirAll(distInfo, 0o755))
+
+	metadata := "Metadata-Version: 2.1\nName: six\nVersion: 1.16.0\nAuthor: Benjamin Peterson\nLicense-File: LICENSE\n"
+	require.NoError(t, os.WriteFile(filepath.Join(distInfo, "METADATA"), []byte(metadata), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(distInfo, "LICENSE"), []byte("Copyright (c) 2010-2020 Benjamin Peterson\n"), 0o644))
+
+	got := extractPythonCopyright([]string{dir}, "pkg:pypi/six@1.16.0")
+	assert.Equal(t, notice{Text: "Copyright (c) 2010-2020 Benjamin Peterson", Source: "License-File LICENSE"}, got)
+}
+
+func TestExtractPythonCopyright_LicenseFileOutsideDistInfo(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	distInfo := filepath.Join(dir, "evil-1.0.0.dist-info")
+	require.NoError(t, os.MkdirAll(distInfo, 0o755))
+
+	metadata := "Metadata-Version: 2.4\nName: evil\nVersion: 1.0.0\nAuthor: Eve\nLicense-File: ../secret.txt\nLicense-File: /etc/passwd\n"
+	require.NoError(t, os.WriteFile(filepath.Join(distInfo, "METADATA"), []byte(metadata), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "secret.txt"), []byte("Copyright (c) Secret\n"), 0o644))
+
+	got := extractPythonCopyright([]string{dir}, "pkg:pypi/evil@1.0.0")
+	assert.Equal(t, notice{Text: "Copyright (c) Eve", Source: "METADATA Author"}, got)
+	assert.Empty(t, pythonLicenseFilePaths(distInfo))
+}
+
+func TestExtractPythonCopyright_AuthorEmail(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	distInfo := filepath.Join(dir, "uvicorn-0.30.0.dist-info")
+	require.NoError(t, os.MkdirAll(distInfo, 0o755))
+
+	metadata := "Metadata-Version: 2.3\nName: uvicorn\nVersion: 0.30.0\nAuthor-email: Tom Christie <tom@example.com>, Marcelo Trylesinski <marcelo@example.com>\n"
+	require.NoError(t, os.WriteFile(filepath.Join(distInfo, "METADATA"), []byte(metadata), 0o644))
+
+	got := extractPythonCopyright([]string{dir}, "pkg:pypi/uvicorn@0.30.0")
+	assert.Equal(t, notice{Text: "Copyright (c) Tom Christie, Marcelo Trylesinski", Source: "METADATA Author-email"}, got)
+}
+
+func TestExtractPythonCopyright_Maintainer(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	distInfo := filepath.Join(dir, "pkg-1.0.0.dist-info")
+	require.NoError(t, os.MkdirAll(distInfo, 0o755))
+
+	metadata := "Metadata-Version: 2.1\nName: pkg\nVersion: 1.0.0\nAuthor: UNKNOWN\nMaintainer: Acme Corp\n"
+	require.NoError(t, os.WriteFile(filepath.Join(distInfo, "METADATA"), []byte(metadata), 0o644))
+
+	got := extractPythonCopyright([]string{dir}, "pkg:pypi/pkg@1.0.0")
+	assert.Equal(t, notice{Text: "Copyright (c) Acme Corp", Source: "METADATA Maintainer"}, got)
+}
+
+func TestExtractPythonCopyright_NormalizedName(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+
+	for _, name := range []string{"zope_interface-6.0.dist-info", "jinja2-3.1.4.dist-info"} {
+		require.NoError(t, os.MkdirAll(filepath.Join(dir, name), 0o755))
+	}
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "zope_interface-6.0.dist-info", "METADATA"), []byte("Author: Zope Foundation and Contributors\n"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "jinja2-3.1.4.dist-info", "METADATA"), []byte("Maintainer: Pallets\n"), 0o644))
+
+	assert.Equal(t, "Copyright (c) Zope Foundation and Contributors", extractPythonCopyright([]string{dir}, "pkg:pypi/zope.interface@6.0").Text)
+	assert.Equal(t, "Copyright (c) Pallets", extractPythonCopyright([]string{dir}, "pkg:pypi/Jinja2@3.1.4").Text)
+}
+
+func TestExtractPythonCopyright_MultipleDirs(t *testing.T) {
+	t.Parallel()
+
+	first, second := t.TempDir(), t.TempDir()
+	distInfo := filepath.Join(second, "attrs-23.2.0.dist-info")
+	require.NoError(t, os.MkdirAll(distInfo, 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(distInfo, "METADATA"), []byte("Author: Hynek Schlawack\n"), 0o644))
+
+	got := extractPythonCopyright([]string{first, second}, "pkg:pypi/attrs@23.2.0")
+	assert.Equal(t, "Copyright (c) Hynek Schlawack", got.Text)
+}
+
+func TestNormalizePythonName(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "zope-interface", normalizePythonName("zope.interface"))
+	assert.Equal(t, "jinja2", normalizePythonName("Jinja2"))
+	assert.Equal(t, "foo-bar", normalizePythonName("Foo__Bar"))
+	assert.Equal(t, "foo-bar", normalizePythonName("foo-._bar"))
+}
+
+func TestNormalizePythonVersion(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]string{
+		"1.0":           "1.0",
+		"v1.0.0":        "1.0.0",
+		"01.02.003":     "1.2.3",
+		"1.0.0-Alpha.1": "1.0.0a1",
+		"1.0beta2":      "1.0b2",
+		"1.0c1":         "1.0rc1",
+		"2.0-r3":        "2.0.post3",
+		"2.0-1":         "2.0.post1",
+		"1.0.dev0":      "1.0.dev0",
+		"0!1.0":         "1.0",
+		"1!1.0":         "1!1.0",
+		"1.0+Ubuntu-1":  "1.0+ubuntu.1",
+		"not a version": "not a version",
+	}
+
+	for in, want := range testCases {
+		assert.Equal(t, want, normalizePythonVersion(in), in)
+	}
+}
+
+func TestPythonSitePackagesDirs(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	for _, dir := range []string{
+		filepath.Join(root, ".venv", "lib", "python3.11", "site-packages"),
+		filepath.Join(root, ".venv", "lib", "python3.12", "site-packages"),
+		filepath.Join(root, "venv", "Lib", "site-packages"),
+	} {
+		require.NoError(t, os.MkdirAll(dir, 0o755))
+	}
+
+	got := pythonSitePackagesDirs([]string{filepath.Join(root, ".venv"), filepath.Join(root, "venv"), filepath.Join(root, "missing")})
+	assert.Equal(t, []string{
+		filepath.Join(root, ".venv", "lib", "python3.11", "site-packages"),
+		filepath.Join(root, ".venv", "lib", "python3.12", "site-packages"),
+		filepath.Join(root, "venv", "Lib", "site-packages"),
+	}, got)
 }
 
 func TestExtractPythonCopyright_EmptyDir(t *testing.T) {
 	t.Parallel()
 
-	assert.Empty(t, extractPythonCopyright("", "pkg:pypi/requests@2.28.0"))
+	assert.Empty(t, extractPythonCopyright(nil, "pkg:pypi/requests@2.28.0"))
 }
@@ -0,0 +1,126 @@
+package generator
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// UnmarshalJSON implements json.Unmarshaler: the corrections are a list or a
+// single object.
+func (c *LicenseCorrections) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+
+	switch {
+	case bytes.Equal(data, []byte("null")):
+		*c = nil
+
+		return nil
+	case bytes.HasPrefix(data, []byte("[")):
+		return json.Unmarshal(data, (*[]LicenseCorrection)(c))
+	case bytes.HasPrefix(data, []byte(`"`)):
+		return fmt.Errorf(`license correction %s: a correction is an object with "license" and "justification" fields, not a license string`, data)
+	}
+
+	var correction LicenseCorrection
+	if err := json.Unmarshal(data, &correction); err != nil {
+		return err
+	}
+
+	*c = LicenseCorrections{correction}
+
+	return nil
+}
+
+// UnmarshalYAML implements yaml.Unmarshaler: the corrections are a list or a
+// single mapping.
+func (c *LicenseCorrections) UnmarshalYAML(node *yaml.Node) error {
+	switch node.Kind {
+	case yaml.SequenceNode:
+		return node.Decode((*[]LicenseCorrection)(c))
+	case yaml.MappingNode:
+		var correction LicenseCorrection
+		if err := node.Decode(&correction); err != nil {
+			return err
+		}
+
+		*c = LicenseCorrections{correction}
+
+		return nil
+	default:
+		if node.Tag == "!!null" {
+			*c = nil
+
+			return nil
+		}
+
+		return fmt.Errorf(`license correction %q: a correction is a mapping with "license" and "justification" keys, not a license string`, node.Value)
+	}
+}
+
+// validateLicenseCorrections checks that every correction has a valid PURL
+// key, a license, a justification and a valid version constraint.
+func validateLicenseCorrections(corrections map[string]LicenseCorrections) error {
+	keys := make([]string, 0, len(corrections))
+	for key := range corrections {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	var errs []error
+
+	for _, key := range keys {
+		p, ok := parsePURL(key)
+		if !ok {
+			errs = append(errs, fmt.Errorf("%s: invalid PURL", key))
+
+			continue
+		}
+
+		for i, c := range corrections[key] {
+			if strings.TrimSpace(c.License) == "" {
+				errs = append(errs, fmt.Errorf("%s[%d]: missing license", key, i))
+			}
+
+			if strings.TrimSpace(c.Justification) == "" {
+				errs = append(errs, fmt.Errorf("%s[%d]: missing justification", key, i))
+			}
+
+			if _, err := parseVersionConstraint(p.Type, c.Versions); err != nil {
+				errs = append(errs, fmt.Errorf("%s[%d]: %w", key, i, err))
+			}
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// matchLicenseCorrection returns the correction that applies to the component
+// with the given PURL, or nil. The matching keys are tried from the most
+// specific one (see matchingPURLKeys): the first correction of the key matching
+// the component version applies, and the next keys are tried when none does.
+func matchLicenseCorrection(purl string, corrections map[string]LicenseCorrections) *AppliedCorrection {
+	p, ok := parsePURL(purl)
+	if !ok {
+		return nil
+	}
+
+	for _, key := range matchingPURLKeys(p, corrections) {
+		for _, c := range corrections[key] {
+			constraint, err := parseVersionConstraint(p.Type, c.Versions)
+			if err != nil || !constraint.matches(p.Version) {
+				continue
+			}
+
+			return &AppliedCorrection{LicenseCorrection: c, PURL: key}
+		}
+	}
+
+	return nil
+}
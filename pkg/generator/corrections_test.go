@@ -0,0 +1,226 @@
+package generator
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func correctionTo(license string) LicenseCorrections {
+	return LicenseCorrections{{License: license, Justification: "LICENSE file."}}
+}
+
+func correctedLicense(purl string, corrections map[string]LicenseCorrections) string {
+	c := matchLicenseCorrection(purl, corrections)
+	if c == nil {
+		return ""
+	}
+
+	return c.License
+}
+
+func TestMatchLicenseCorrection_ExactPURL(t *testing.T) {
+	t.Parallel()
+
+	corrections := map[string]LicenseCorrections{
+		"pkg:npm/config-chain@1.1.13": correctionTo("MIT"),
+	}
+
+	assert.Equal(t, "MIT", correctedLicense("pkg:npm/config-chain@1.1.13", corrections))
+	assert.Empty(t, correctedLicense("pkg:npm/config-chain@2.0.0", corrections))
+}
+
+func TestMatchLicenseCorrection_PrefixMatch(t *testing.T) {
+	t.Parallel()
+
+	corrections := map[string]LicenseCorrections{
+		"pkg:golang/std": correctionTo("BSD-3-Clause"),
+	}
+
+	assert.Equal(t, "BSD-3-Clause", correctedLicense("pkg:golang/std@go1.25.3", corrections))
+	assert.Equal(t, "BSD-3-Clause", correctedLicense("pkg:golang/std@go1.24.0", corrections))
+	assert.Empty(t, correctedLicense("pkg:golang/github.com/foo/bar@v1.0.0", corrections))
+}
+
+func TestMatchLicenseCorrection_StripsQualifiers(t *testing.T) {
+	t.Parallel()
+
+	corrections := map[string]LicenseCorrections{
+		"pkg:golang/github.com/ghodss/yaml": correctionTo("MIT"),
+	}
+
+	assert.Equal(t, "MIT", correctedLicense("pkg:golang/github.com/ghodss/yaml@v1.0.0?goarch=arm64&goos=darwin&type=module", corrections))
+}
+
+func TestMatchLicenseCorrection_EncodedScope(t *testing.T) {
+	t.Parallel()
+
+	corrections := map[string]LicenseCorrections{
+		"pkg:npm/%40traefik/ui": correctionTo("Apache-2.0"),
+	}
+
+	assert.Equal(t, "Apache-2.0", correctedLicense("pkg:npm/@traefik/ui@1.0.0", corrections))
+	assert.Equal(t, "Apache-2.0", correctedLicense("pkg:npm/%40traefik/ui@1.0.0", corrections))
+	assert.Empty(t, correctedLicense("pkg:npm/@traefik/ui-kit@1.0.0", corrections))
+}
+
+func TestMatchLicenseCorrection_SubPackageAndMajorVersion(t *testing.T) {
+	t.Parallel()
+
+	corrections := map[string]LicenseCorrections{
+		"pkg:golang/github.com/nrdcg/oci-go-sdk": correctionTo("UPL-1.0"),
+	}
+
+	// Sub-package with embedded major version in path.
+	assert.Equal(t, "UPL-1.0", correctedLicense("pkg:golang/github.com/nrdcg/oci-go-sdk/v65/common@v65.0.0", corrections))
+	// Direct module with version only.
+	assert.Equal(t, "UPL-1.0", correctedLicense("pkg:golang/github.com/nrdcg/oci-go-sdk@v65.0.0", corrections))
+	// Unrelated package must not match.
+	assert.Empty(t, correctedLicense("pkg:golang/github.com/other/pkg@v1.0.0", corrections))
+}
+
+func TestMatchLicenseCorrection_NilMap(t *testing.T) {
+	t.Parallel()
+
+	assert.Nil(t, matchLicenseCorrection("pkg:npm/foo@1.0.0", nil))
+}
+
+func TestMatchLicenseCorrection_LongestPrefixWins(t *testing.T) {
+	t.Parallel()
+
+	corrections := map[string]LicenseCorrections{
+		"pkg:golang/github.com/foo":             correctionTo("MIT"),
+		"pkg:golang/github.com/foo/bar":         correctionTo("Apache-2.0"),
+		"pkg:golang/github.com/foo/bar/baz":     correctionTo("ISC"),
+		"pkg:golang/github.com/foo/bar@v1.0.0":  correctionTo("BSD-3-Clause"),
+		"pkg:golang/github.com/foo/bar/baz/qux": correctionTo("0BSD"),
+	}
+
+	for range 20 {
+		assert.Equal(t, "ISC", correctedLicense("pkg:golang/github.com/foo/bar/baz/sub@v1.2.0", corrections))
+	}
+
+	assert.Equal(t, "Apache-2.0", correctedLicense("pkg:golang/github.com/foo/bar@v1.2.0", corrections))
+	assert.Equal(t, "BSD-3-Clause", correctedLicense("pkg:golang/github.com/foo/bar@v1.0.0", corrections))
+	assert.Equal(t, "MIT", correctedLicense("pkg:golang/github.com/foo/other@v1.0.0", corrections))
+}
+
+func TestMatchLicenseCorrection_Versions(t *testing.T) {
+	t.Parallel()
+
+	corrections := map[string]LicenseCorrections{
+		"pkg:golang/github.com/hashicorp/terraform": {
+			{Versions: "< v1.6.0", License: "MPL-2.0", Justification: "Released under MPL-2.0.", Reference: "https://www.hashicorp.com/license-faq"},
+			{Versions: ">= v1.6.0", License: "BUSL-1.1", Justification: "Relicensed to BUSL-1.1 in 1.6.0."},
+		},
+		"pkg:golang/github.com/hashicorp": correctionTo("MPL-2.0"),
+		"pkg:pypi/foo": {
+			{Versions: "~=2.2", License: "Apache-2.0", Justification: "Relicensed in 2.2."},
+		},
+		"pkg:pypi/bar": {
+			{Versions: "<2.0", License: "MIT", Justification: "Relicensed in 2.0."},
+		},
+	}
+
+	testCases := []struct {
+		purl     string
+		expected string
+		key      string
+	}{
+		{purl: "pkg:golang/github.com/hashicorp/terraform@v1.5.7", expected: "MPL-2.0", key: "pkg:golang/github.com/hashicorp/terraform"},
+		{purl: "pkg:golang/github.com/hashicorp/terraform@v1.6.0", expected: "BUSL-1.1", key: "pkg:golang/github.com/hashicorp/terraform"},
+		{purl: "pkg:golang/github.com/hashicorp/terraform@v1.6.0-alpha20230816", expected: "MPL-2.0", key: "pkg:golang/github.com/hashicorp/terraform"},
+		{purl: "pkg:golang/github.com/hashicorp/terraform@v1.5.8-0.20230901120000-0123456789ab", expected: "MPL-2.0", key: "pkg:golang/github.com/hashicorp/terraform"},
+		{purl: "pkg:golang/github.com/hashicorp/terraform@v1.6.1-0.20231001120000-0123456789ab", expected: "BUSL-1.1", key: "pkg:golang/github.com/hashicorp/terraform"},
+		// Without a version, the version-bound corrections do not apply.
+		{purl: "pkg:golang/github.com/hashicorp/terraform", expected: "MPL-2.0", key: "pkg:golang/github.com/hashicorp"},
+		{purl: "pkg:pypi/foo@2.5", expected: "Apache-2.0", key: "pkg:pypi/foo"},
+		{purl: "pkg:pypi/foo@3.0", expected: ""},
+		{purl: "pkg:pypi/bar@2.0rc1", expected: "MIT", key: "pkg:pypi/bar"},
+		{purl: "pkg:pypi/bar@2.0.post1", expected: ""},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.purl, func(t *testing.T) {
+			t.Parallel()
+
+			c := matchLicenseCorrection(test.purl, corrections)
+			if test.expected == "" {
+				assert.Nil(t, c)
+
+				return
+			}
+
+			require.NotNil(t, c)
+			assert.Equal(t, test.expected, c.License)
+			assert.Equal(t, test.key, c.PURL)
+			assert.NotEmpty(t, c.Justification)
+		})
+	}
+}
+
+func TestLicenseCorrections_Unmarshal(t *testing.T) {
+	t.Parallel()
+
+	var fromJSON map[string]LicenseCorrections
+	require.NoError(t, json.Unmarshal([]byte(`{
+		"pkg:npm/a": {"license": "MIT", "justification": "LICENSE file."},
+		"pkg:npm/b": [{"versions": "<2", "license": "MIT", "justification": "LICENSE file."}],
+		"pkg:npm/c": null
+	}`), &fromJSON))
+
+	assert.Equal(t, correctionTo("MIT"), fromJSON["pkg:npm/a"])
+	assert.Equal(t, LicenseCorrections{{Versions: "<2", License: "MIT", Justification: "LICENSE file."}}, fromJSON["pkg:npm/b"])
+	assert.Contains(t, fromJSON, "pkg:npm/c")
+	assert.Nil(t, fromJSON["pkg:npm/c"])
+
+	var fromYAML map[string]LicenseCorrections
+	require.NoError(t, yaml.Unmarshal([]byte(`
+pkg:npm/a:
+  license: MIT
+  justification: LICENSE file.
+pkg:npm/b:
+  - versions: <2
+    license: MIT
+    justification: LICENSE file.
+pkg:npm/c: ~
+`), &fromYAML))
+
+	assert.Equal(t, fromJSON, fromYAML)
+
+	var legacy map[string]LicenseCorrections
+	require.ErrorContains(t, json.Unmarshal([]byte(`{"pkg:npm/a": "MIT"}`), &legacy), `license correction "MIT": a correction is an object with "license" and "justification" fields`)
+	require.ErrorContains(t, yaml.Unmarshal([]byte("pkg:npm/a: MIT\n"), &legacy), `license correction "MIT": a correction is a mapping with "license" and "justification" keys`)
+}
+
+func TestValidateLicenseCorrections(t *testing.T) {
+	t.Parallel()
+
+	require.NoError(t, validateLicenseCorrections(map[string]LicenseCorrections{
+		"pkg:npm/a": {{Versions: "^1.2.0 || >= 3", License: "MIT", Justification: "LICENSE file."}},
+	}))
+
+	err := validateLicenseCorrections(map[string]LicenseCorrections{
+		"not-a-purl": correctionTo("MIT"),
+		"pkg:npm/a":  {{License: "MIT"}},
+		"pkg:npm/b":  {{Justification: "LICENSE file."}},
+		"pkg:npm/c":  {{Versions: ">= one", License: "MIT", Justification: "LICENSE file."}},
+	})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "not-a-purl: invalid PURL")
+	assert.Contains(t, err.Error(), "pkg:npm/a[0]: missing justification")
+	assert.Contains(t, err.Error(), "pkg:npm/b[0]: missing license")
+	assert.Contains(t, err.Error(), `pkg:npm/c[0]: invalid version "one"`)
+}
+
+func TestEmbeddedLicenseCorrections(t *testing.T) {
+	t.Parallel()
+
+	corrections, err := readJSON[map[string]LicenseCorrections](embedded.ReadFile, embeddedLicenseCorrectionsPath)
+	require.NoError(t, err)
+
+	require.NoError(t, validateLicenseCorrections(corrections))
+}
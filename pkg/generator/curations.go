@@ -0,0 +1,85 @@
+package generator
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+)
+
+// isEmpty reports whether the curation sets nothing, such as a null entry,
+// which removes the inherited entry.
+func (c Curation) isEmpty() bool {
+	return c.Name == "" && c.URL == "" && len(c.Copyright) == 0 && len(c.Notes) == 0 && len(c.Modifications) == 0
+}
+
+// validateCurations checks that every curation has a valid PURL key.
+func validateCurations(curations map[string]Curation) error {
+	keys := make([]string, 0, len(curations))
+	for key := range curations {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	var errs []error
+
+	for _, key := range keys {
+		if _, ok := parsePURL(key); !ok {
+			errs = append(errs, fmt.Errorf("%s: invalid PURL", key))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// matchCuration returns the key and the curation that apply to the component
+// with the given PURL: the most specific matching key wins (see
+// matchingPURLKeys).
+func matchCuration(purl string, curations map[string]Curation) (string, Curation, bool) {
+	p, ok := parsePURL(purl)
+	if !ok {
+		return "", Curation{}, false
+	}
+
+	keys := matchingPURLKeys(p, curations)
+	if len(keys) == 0 {
+		return "", Curation{}, false
+	}
+
+	return keys[0], curations[keys[0]], true
+}
+
+// applyCuration overrides the metadata of out with its curation, if any.
+func applyCuration(out OutComponent, curations map[string]Curation) OutComponent {
+	key, curation, ok := matchCuration(out.PURL, curations)
+	if !ok {
+		return out
+	}
+
+	if curation.Name != "" {
+		out.Name = curation.Name
+	}
+
+	if curation.URL != "" {
+		out.URL = curation.URL
+	}
+
+	if len(curation.Copyright) > 0 {
+		out.Copyright = strings.Join(curation.Copyright, "\n")
+		out.CopyrightSource = "curation " + key
+	}
+
+	out.Notes = curation.Notes
+	out.Modifications = curation.Modifications
+	out.Curation = key
+
+	log.Info().
+		Str("component", out.Name+"@"+out.Version).
+		Str("curation", key).
+		Msg("Component curated.")
+
+	return out
+}
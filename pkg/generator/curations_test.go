@@ -0,0 +1,131 @@
+package generator
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMatchCuration_MostSpecificKeyWins(t *testing.T) {
+	t.Parallel()
+
+	curations := map[string]Curation{
+		"pkg:golang/github.com/traefik":                {Notes: []string{"org"}},
+		"pkg:golang/github.com/traefik/yaegi":          {Notes: []string{"module"}},
+		"pkg:golang/github.com/traefik/yaegi@v0.16.1":  {Notes: []string{"version"}},
+		"pkg:golang/github.com/traefik/yaegi-examples": {Notes: []string{"other"}},
+	}
+
+	testCases := []struct {
+		purl     string
+		expected string
+	}{
+		{purl: "pkg:golang/github.com/traefik/yaegi@v0.16.1", expected: "pkg:golang/github.com/traefik/yaegi@v0.16.1"},
+		{purl: "pkg:golang/github.com/traefik/yaegi@v0.16.0", expected: "pkg:golang/github.com/traefik/yaegi"},
+		{purl: "pkg:golang/github.com/traefik/yaegi/interp@v0.16.0", expected: "pkg:golang/github.com/traefik/yaegi"},
+		{purl: "pkg:golang/github.com/traefik/paerser@v0.2.0", expected: "pkg:golang/github.com/traefik"},
+		{purl: "pkg:golang/github.com/foo/bar@v1.0.0"},
+		{purl: "not-a-purl"},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.purl, func(t *testing.T) {
+			t.Parallel()
+
+			key, _, ok := matchCuration(test.purl, curations)
+			assert.Equal(t, test.expected != "", ok)
+			assert.Equal(t, test.expected, key)
+		})
+	}
+}
+
+func TestApplyCuration(t *testing.T) {
+	t.Parallel()
+
+	curations := map[string]Curation{
+		"pkg:golang/github.com/foo/bar": {
+			Name:          "Bar",
+			URL:           "https://example.com/bar",
+			Copyright:     []string{"Copyright (c) 2020 Foo", "Copyright (c) 2024 Traefik Labs"},
+			Notes:         []string{"Vendored in internal/bar."},
+			Modifications: []string{"Modified by Traefik Labs to support HTTP/3."},
+		},
+		"pkg:npm/baz": {Notes: []string{"Bundled in the web UI."}},
+	}
+
+	out := applyCuration(OutComponent{
+		Name:            "github.com/foo/bar",
+		Version:         "v1.0.0",
+		PURL:            "pkg:golang/github.com/foo/bar@v1.0.0",
+		URL:             "https://github.com/foo/bar",
+		Copyright:       "Copyright (c) Foo",
+		CopyrightSource: "LICENSE",
+	}, curations)
+
+	assert.Equal(t, OutComponent{
+		Name:            "Bar",
+		Version:         "v1.0.0",
+		PURL:            "pkg:golang/github.com/foo/bar@v1.0.0",
+		URL:             "https://example.com/bar",
+		Copyright:       "Copyright (c) 2020 Foo\nCopyright (c) 2024 Traefik Labs",
+		CopyrightSource: "curation pkg:golang/github.com/foo/bar",
+		Notes:           []string{"Vendored in internal/bar."},
+		Modifications:   []string{"Modified by Traefik Labs to support HTTP/3."},
+		Curation:        "pkg:golang/github.com/foo/bar",
+	}, out)
+
+	// Empty fields keep the component metadata.
+	out = applyCuration(OutComponent{Name: "baz", PURL: "pkg:npm/baz@1.0.0", Copyright: "Copyright Baz", CopyrightSource: "sbom"}, curations)
+
+	assert.Equal(t, "baz", out.Name)
+	assert.Equal(t, "Copyright Baz", out.Copyright)
+	assert.Equal(t, "sbom", out.CopyrightSource)
+	assert.Equal(t, []string{"Bundled in the web UI."}, out.Notes)
+}
+
+func TestBuildIndex_Curations(t *testing.T) {
+	t.Parallel()
+
+	components := []Component{
+		{Name: "bar", Version: "1.0.0", PURL: "pkg:npm/bar@1.0.0", Licenses: []LicenseChoice{{License: &License{ID: "Apache-2.0"}}}},
+	}
+	curations := map[string]Curation{
+		"pkg:npm/bar": {Modifications: []string{"Modified by Traefik Labs."}},
+	}
+
+	byLicense, byKey := buildIndex(components, inputs{curations: curations}, copyrightEnricher{}, nil)
+
+	require.Len(t, byLicense["Apache-2.0"], 1)
+	assert.Equal(t, []string{"Modified by Traefik Labs."}, byLicense["Apache-2.0"][0].Modifications)
+
+	// Curated components are listed in the notices even without copyright.
+	notices := buildNotices(byKey)
+	require.Len(t, notices, 1)
+	assert.Equal(t, "pkg:npm/bar", notices[0].Curation)
+}
+
+func TestValidateCurations(t *testing.T) {
+	t.Parallel()
+
+	require.NoError(t, validateCurations(map[string]Curation{"pkg:npm/a": {Name: "A"}}))
+
+	err := validateCurations(map[string]Curation{"npm/a": {Name: "A"}})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "npm/a: invalid PURL")
+}
+
+func TestMergeDataMaps_Curations(t *testing.T) {
+	t.Parallel()
+
+	var repo map[string]Curation
+	require.NoError(t, json.Unmarshal([]byte(`{"pkg:npm/a": null, "pkg:npm/b": {"url": "https://example.com/b"}}`), &repo))
+
+	merged := mergeDataMaps("curations", []dataLayer[map[string]Curation]{
+		{name: embeddedLayer, data: map[string]Curation{"pkg:npm/a": {Name: "A"}}},
+		{name: "curations.json", data: repo},
+	}, Curation.isEmpty)
+
+	assert.Equal(t, map[string]Curation{"pkg:npm/b": {URL: "https://example.com/b"}}, merged)
+}
@@ -0,0 +1,124 @@
+package generator
+
+import (
+	"sync"
+
+	classifier "github.com/google/licenseclassifier/v2"
+	"github.com/google/licenseclassifier/v2/assets"
+	"github.com/rs/zerolog/log"
+)
+
+// licenseMatch is a license recognized in a license file.
+type licenseMatch struct {
+	ID         string
+	Confidence float64
+}
+
+// licenseDetector classifies license files against the SPDX license texts known
+// to the license classifier.
+type licenseDetector struct {
+	threshold  float64
+	licenseMap map[string]string
+	classify   func(text string) []licenseMatch
+
+	// detected caches the IDs found in each license file by text, as the same
+	// files are classified by buildIndex then for each license text.
+	detected map[string][]string
+}
+
+// newLicenseDetector returns a license detector, or nil when license detection
+// is disabled. The detected names go through licenseMap like declared ones.
+func newLicenseDetector(cfg Config, licenseMap map[string]string) *licenseDetector {
+	if !cfg.DetectLicenses {
+		return nil
+	}
+
+	return &licenseDetector{
+		threshold:  cfg.LicenseDetectionThreshold,
+		licenseMap: licenseMap,
+		classify:   googleClassify(),
+	}
+}
+
+// detect returns the sorted SPDX IDs of the licenses found in files with a
+// confidence at or above the detector threshold.
+func (d *licenseDetector) detect(files []licenseFile) []string {
+	if d == nil {
+		return nil
+	}
+
+	var ids []string
+
+	for _, f := range files {
+		ids = append(ids, d.detectFile(f)...)
+	}
+
+	return uniqSorted(ids)
+}
+
+// detectFile returns the SPDX IDs of the licenses found in f, classifying its
+// text only once.
+func (d *licenseDetector) detectFile(f licenseFile) []string {
+	if ids, ok := d.detected[f.Text]; ok {
+		return ids
+	}
+
+	var ids []string
+
+	for _, m := range d.classify(f.Text) {
+		if m.Confidence >= d.threshold {
+			ids = append(ids, resolveSingleLicense(m.ID, d.licenseMap))
+		}
+	}
+
+	if d.detected == nil {
+		d.detected = map[string][]string{}
+	}
+
+	d.detected[f.Text] = ids
+
+	return ids
+}
+
+// googleClassify returns a classification function backed by the Google
+// license classifier, the one Trivy relies on. The classifier is loaded on
+// first use since loading it is expensive.
+func googleClassify() func(text string) []licenseMatch {
+	var (
+		once sync.Once
+		mu   sync.Mutex
+		cf   *classifier.Classifier
+	)
+
+	return func(text string) []licenseMatch {
+		once.Do(func() {
+			var err error
+
+			cf, err = assets.DefaultClassifier()
+			if err != nil {
+				log.Error().Err(err).Msg("Failed to load the license classifier.")
+			}
+		})
+
+		if cf == nil {
+			return nil
+		}
+
+		// Classifier.Match is not thread safe.
+		mu.Lock()
+		defer mu.Unlock()
+
+		var matches []licenseMatch
+
+		for _, m := range cf.Match(cf.Normalize([]byte(text))).Matches {
+			// The classifier also reports license headers and copyright notices.
+			if m.MatchType != "License" {
+				continue
+			}
+
+			matches = append(matches, licenseMatch{ID: m.Name, Confidence: m.Confidence})
+		}
+
+		return matches
+	}
+}
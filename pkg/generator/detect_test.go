@@ -0,0 +1,167 @@
+package generator
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const mitLicenseText = `MIT License
+
+Copyright (c) 2020 Foo
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+`
+
+func TestNewLicenseDetector_Disabled(t *testing.T) {
+	t.Parallel()
+
+	assert.Nil(t, newLicenseDetector(Config{}, nil))
+	assert.Empty(t, (*licenseDetector)(nil).detect([]licenseFile{{Name: "LICENSE", Text: mitLicenseText}}))
+}
+
+func TestLicenseDetector_Threshold(t *testing.T) {
+	t.Parallel()
+
+	d := &licenseDetector{
+		threshold: 0.9,
+		classify: func(text string) []licenseMatch {
+			if text == "dual" {
+				return []licenseMatch{{ID: "MIT", Confidence: 0.99}, {ID: "Apache-2.0", Confidence: 0.95}}
+			}
+
+			return []licenseMatch{{ID: "BSD-3-Clause", Confidence: 0.5}}
+		},
+	}
+
+	got := d.detect([]licenseFile{{Name: "LICENSE", Text: "dual"}, {Name: "COPYING", Text: "weak"}})
+	assert.Equal(t, []string{"Apache-2.0", "MIT"}, got)
+}
+
+func TestLicenseDetector_GoogleClassifier(t *testing.T) {
+	t.Parallel()
+
+	d := newLicenseDetector(Config{DetectLicenses: true, LicenseDetectionThreshold: 0.9}, nil)
+
+	assert.Equal(t, []string{"MIT"}, d.detect([]licenseFile{{Name: "LICENSE", Text: mitLicenseText}}))
+}
+
+func TestLicenseDetector_Cache(t *testing.T) {
+	t.Parallel()
+
+	var calls int
+
+	d := &licenseDetector{
+		threshold: 0.9,
+		classify: func(string) []licenseMatch {
+			calls++
+
+			return []licenseMatch{{ID: "MIT", Confidence: 0.99}}
+		},
+	}
+
+	files := []licenseFile{{Name: "LICENSE", Text: "mit"}}
+
+	assert.Equal(t, []string{"MIT"}, d.detect(files))
+	assert.Equal(t, []string{"MIT"}, d.detect(files))
+	assert.Equal(t, 1, calls)
+}
+
+func TestLicenseDetector_LicenseMap(t *testing.T) {
+	t.Parallel()
+
+	d := &licenseDetector{
+		threshold:  0.9,
+		licenseMap: map[string]string{"BSD-2-Clause-FreeBSD": "BSD-2-Clause"},
+		classify: func(string) []licenseMatch {
+			return []licenseMatch{{ID: "BSD-2-Clause-FreeBSD", Confidence: 0.99}}
+		},
+	}
+
+	assert.Equal(t, []string{"BSD-2-Clause"}, d.detect([]licenseFile{{Name: "LICENSE", Text: "freebsd"}}))
+}
+
+func TestGoogleClassify_LicenseMatchesOnly(t *testing.T) {
+	t.Parallel()
+
+	for _, m := range googleClassify()(mitLicenseText) {
+		assert.Equal(t, "MIT", m.ID)
+	}
+
+	// An Apache-2.0 source file header is a "Header" match, not a license text.
+	header := `Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+`
+	assert.Empty(t, googleClassify()(header))
+}
+
+func TestApplyDetectedLicenses(t *testing.T) {
+	t.Parallel()
+
+	c := Component{Name: "foo", Version: "1.0.0", PURL: "pkg:npm/foo@1.0.0"}
+
+	ids, source := applyDetectedLicenses(c, nil, licenseSourceSBOM, []string{"MIT"})
+	assert.Equal(t, []string{"MIT"}, ids)
+	assert.Equal(t, licenseSourceDetected, source)
+
+	// Declared licenses are never replaced, only reported on disagreement.
+	ids, source = applyDetectedLicenses(c, []string{"Apache-2.0"}, licenseSourceSBOM, []string{"MIT"})
+	assert.Equal(t, []string{"Apache-2.0"}, ids)
+	assert.Equal(t, licenseSourceSBOM, source)
+
+	ids, source = applyDetectedLicenses(c, nil, licenseSourceSBOM, nil)
+	assert.Empty(t, ids)
+	assert.Equal(t, licenseSourceSBOM, source)
+}
+
+func TestBuildIndex_DetectedLicense(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	pkgDir := filepath.Join(dir, "foo")
+	require.NoError(t, os.MkdirAll(pkgDir, 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(pkgDir, "LICENSE"), []byte(mitLicenseText), 0o644))
+
+	detector := &licenseDetector{
+		threshold: 0.9,
+		classify: func(string) []licenseMatch {
+			return []licenseMatch{{ID: "MIT", Confidence: 1}}
+		},
+	}
+
+	components := []Component{{Name: "foo", Version: "1.0.0", PURL: "pkg:npm/foo@1.0.0"}}
+
+	byLicense, byKey := buildIndex(components, inputs{}, copyrightEnricher{nodeModulesDir: dir}, detector)
+
+	require.Contains(t, byLicense, "MIT")
+	assert.Equal(t, []string{"MIT"}, byKey["pkg:npm/foo@1.0.0"].LicenseIDs)
+	assert.Equal(t, licenseSourceDetected, byKey["pkg:npm/foo@1.0.0"].LicenseSource)
+}
@@ -0,0 +1,131 @@
+package generator
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/aquasecurity/trivy/pkg/licensing/expression"
+	"github.com/rs/zerolog/log"
+)
+
+// loadExtraComponents reads the extra components, given inline in the
+// configuration file or by path, and converts them to SBOM components.
+func loadExtraComponents(cfg Config) ([]Component, error) {
+	extras := cfg.ExtraComponents
+
+	if extras == nil && cfg.ExtraComponentsPath != "" {
+		var err error
+
+		extras, err = readJSON[[]ExtraComponent](os.ReadFile, cfg.ExtraComponentsPath)
+		if err != nil {
+			return nil, err
+		}
+
+		dir := filepath.Dir(cfg.ExtraComponentsPath)
+		for i := range extras {
+			extras[i].LicenseFile = resolvePath(dir, extras[i].LicenseFile)
+		}
+	}
+
+	if err := validateExtraComponents(extras); err != nil {
+		return nil, err
+	}
+
+	components := make([]Component, 0, len(extras))
+
+	for _, e := range extras {
+		c, err := e.component()
+		if err != nil {
+			return nil, err
+		}
+
+		components = append(components, c)
+	}
+
+	if len(components) > 0 {
+		log.Info().Int("count", len(components)).Msg("Extra components added.")
+	}
+
+	return components, nil
+}
+
+// validateExtraComponents checks that every extra component has a name, a
+// license, a valid PURL when set, and a single license when it has a license
+// file.
+func validateExtraComponents(extras []ExtraComponent) error {
+	var errs []error
+
+	for i, e := range extras {
+		id := fmt.Sprintf("extra component %d", i)
+		if e.Name != "" {
+			id = fmt.Sprintf("extra component %q", e.Name)
+		} else {
+			errs = append(errs, fmt.Errorf("%s: missing name", id))
+		}
+
+		license := strings.TrimSpace(e.License)
+		if license == "" {
+			errs = append(errs, fmt.Errorf("%s: missing license", id))
+		}
+
+		if e.PURL != "" {
+			if _, ok := parsePURL(e.PURL); !ok {
+				errs = append(errs, fmt.Errorf("%s: invalid PURL %q", id, e.PURL))
+			}
+		}
+
+		if e.LicenseFile != "" && strings.ContainsAny(license, " ()") {
+			errs = append(errs, fmt.Errorf("%s: a license file requires a single license, got %q", id, license))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// component converts the extra component to an SBOM component, reading its
+// license file.
+func (e ExtraComponent) component() (Component, error) {
+	c := Component{
+		Type:      e.Type,
+		Name:      e.Name,
+		Version:   e.Version,
+		PURL:      e.PURL,
+		Copyright: e.Copyright,
+	}
+
+	if c.Type == "" {
+		c.Type = "library"
+	}
+
+	if e.URL != "" {
+		c.ExternalReferences = []ExternalReference{{Type: "website", URL: e.URL}}
+	}
+
+	license := strings.TrimSpace(e.License)
+
+	if e.LicenseFile == "" {
+		c.Licenses = []LicenseChoice{{Expression: license}}
+
+		return c, nil
+	}
+
+	text, err := os.ReadFile(e.LicenseFile)
+	if err != nil {
+		return Component{}, fmt.Errorf("extra component %q: failed to read license file: %w", e.Name, err)
+	}
+
+	l := &License{ID: license, Text: &AttachedText{Content: string(text)}}
+
+	// A license that is not an SPDX one is a name, resolved through the license
+	// map or to a LicenseRef- ID, e.g. "LicenseRef-Font-Awesome-Free".
+	if _, ok := expression.SPDXLicenseID(license); !ok && !strings.HasPrefix(license, "LicenseRef-") {
+		l.ID, l.Name = "", license
+	}
+
+	c.Licenses = []LicenseChoice{{License: l}}
+
+	return c, nil
+}
@@ -0,0 +1,162 @@
+package generator
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadExtraComponents(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "licenses"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "licenses", "fontawesome.txt"), []byte("Font Awesome Free License\n"), 0o644))
+
+	path := filepath.Join(dir, "extra-components.json")
+	require.NoError(t, os.WriteFile(path, []byte(`[
+		{
+			"name": "Font Awesome Free",
+			"version": "6.5.1",
+			"license": "LicenseRef-Font-Awesome-Free",
+			"copyright": "Copyright (c) 2024 Fonticons, Inc.",
+			"url": "https://fontawesome.com",
+			"licenseFile": "licenses/fontawesome.txt"
+		},
+		{
+			"name": "miniz",
+			"version": "3.0.2",
+			"purl": "pkg:github/richgel999/miniz@3.0.2",
+			"type": "file",
+			"license": "MIT OR Unlicense"
+		}
+	]`), 0o644))
+
+	components, err := loadExtraComponents(Config{ExtraComponentsPath: path})
+	require.NoError(t, err)
+
+	assert.Equal(t, []Component{
+		{
+			Type:               "library",
+			Name:               "Font Awesome Free",
+			Version:            "6.5.1",
+			Copyright:          "Copyright (c) 2024 Fonticons, Inc.",
+			ExternalReferences: []ExternalReference{{Type: "website", URL: "https://fontawesome.com"}},
+			Licenses: []LicenseChoice{{License: &License{
+				ID:   "LicenseRef-Font-Awesome-Free",
+				Text: &AttachedText{Content: "Font Awesome Free License\n"},
+			}}},
+		},
+		{
+			Type:     "file",
+			Name:     "miniz",
+			Version:  "3.0.2",
+			PURL:     "pkg:github/richgel999/miniz@3.0.2",
+			Licenses: []LicenseChoice{{Expression: "MIT OR Unlicense"}},
+		},
+	}, components)
+
+	// Inline components take precedence over the file.
+	components, err = loadExtraComponents(Config{
+		ExtraComponentsPath: path,
+		ExtraComponents:     []ExtraComponent{{Name: "snippet", License: "CC-BY-SA-4.0"}},
+	})
+	require.NoError(t, err)
+	require.Len(t, components, 1)
+	assert.Equal(t, "snippet", components[0].Name)
+
+	_, err = loadExtraComponents(Config{ExtraComponents: []ExtraComponent{{Name: "font", License: "OFL-1.1", LicenseFile: filepath.Join(dir, "missing.txt")}}})
+	require.Error(t, err)
+}
+
+func TestValidateExtraComponents(t *testing.T) {
+	t.Parallel()
+
+	require.NoError(t, validateExtraComponents(nil))
+
+	err := validateExtraComponents([]ExtraComponent{
+		{License: "MIT"},
+		{Name: "foo"},
+		{Name: "bar", License: "MIT", PURL: "bar"},
+		{Name: "baz", License: "MIT OR Apache-2.0", LicenseFile: "LICENSE"},
+	})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "extra component 0: missing name")
+	assert.Contains(t, err.Error(), `extra component "foo": missing license`)
+	assert.Contains(t, err.Error(), `extra component "bar": invalid PURL "bar"`)
+	assert.Contains(t, err.Error(), `extra component "baz": a license file requires a single license`)
+}
+
+func TestBuildIndex_ExtraComponents(t *testing.T) {
+	t.Parallel()
+
+	extras := []ExtraComponent{
+		{Name: "Font Awesome Free", Version: "6.5.1", License: "OFL-1.1", Copyright: "Copyright (c) 2024 Fonticons, Inc.", URL: "https://fontawesome.com"},
+		{Name: "snippet", License: "BUSL-1.1"},
+	}
+
+	var components []Component
+	for _, e := range extras {
+		c, err := e.component()
+		require.NoError(t, err)
+
+		components = append(components, c)
+	}
+
+	filters := Filters{FilterRules: FilterRules{Licenses: []*regexp.Regexp{regexp.MustCompile(`^BUSL-`)}}}
+
+	byLicense, byKey := buildIndex(components, inputs{filters: filters}, copyrightEnricher{}, nil)
+
+	assert.NotContains(t, byLicense, "BUSL-1.1")
+	require.Contains(t, byKey, "Font Awesome Free@6.5.1")
+
+	font := byKey["Font Awesome Free@6.5.1"]
+	assert.Equal(t, []string{"OFL-1.1"}, font.LicenseIDs)
+	assert.Equal(t, "https://fontawesome.com", font.URL)
+	assert.Equal(t, "Copyright (c) 2024 Fonticons, Inc.", font.Copyright)
+}
+
+func TestBuildModel_ExtraComponentLicenseFiles(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "fontawesome.txt"), []byte("Font Awesome Free License\n"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "inter.txt"), []byte("Copyright 2020 The Inter Project Authors\n\nSIL Open Font License\n"), 0o644))
+
+	licensesDir := filepath.Join(dir, "licenses")
+	require.NoError(t, os.MkdirAll(licensesDir, 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(licensesDir, "MIT.txt"), []byte("MIT License\n"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(licensesDir, "OFL-1.1.txt"), []byte("SIL Open Font License\n"), 0o644))
+
+	cfg := Config{
+		OutLicensesDir:             licensesDir,
+		DisableFirstPartyDetection: true,
+		ExtraComponents: []ExtraComponent{
+			{Name: "Font Awesome Free", Version: "6.5.1", License: "Font-Awesome-Free", LicenseFile: filepath.Join(dir, "fontawesome.txt")},
+			{Name: "Inter", Version: "4.0", License: "OFL-1.1", LicenseFile: filepath.Join(dir, "inter.txt")},
+			{Name: "miniz", Version: "3.0.2", License: "MIT"},
+		},
+	}
+
+	extras, err := loadExtraComponents(cfg)
+	require.NoError(t, err)
+
+	model, err := buildModel(t.Context(), cfg, inputs{extraComponents: extras})
+	require.NoError(t, err)
+
+	texts := map[string]string{}
+	for _, l := range model.Licenses {
+		texts[l.ID] = l.Text
+	}
+
+	assert.Equal(t, map[string]string{
+		"LicenseRef-Font-Awesome-Free": "Font Awesome Free License",
+		"MIT":                          "MIT License\n",
+		"OFL-1.1":                      "Copyright 2020 The Inter Project Authors\n\nSIL Open Font License",
+	}, texts)
+	assert.Equal(t, []string{"LicenseRef-Font-Awesome-Free"}, model.byKey["Font Awesome Free@6.5.1"].LicenseIDs)
+}
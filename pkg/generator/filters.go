@@ -0,0 +1,131 @@
+package generator
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+)
+
+// defaultScope is the CycloneDX scope of a component that declares none.
+const defaultScope = "required"
+
+// excludedBy returns the exclusion rule matching the component, or an empty
+// string when the component is kept. licenseIDs are the licenses resolved for
+// the component. First-party components are excluded as well. Include rules
+// take precedence over exclusion rules.
+func excludedBy(c Component, licenseIDs []string, filters Filters) string {
+	rule := filters.match(c, licenseIDs)
+	if rule == "" {
+		rule = filters.firstParty.match(c)
+	}
+
+	if rule == "" {
+		return ""
+	}
+
+	if filters.Include.match(c, licenseIDs) != "" {
+		return ""
+	}
+
+	return rule
+}
+
+// match returns a description of the first rule matching the component, or an
+// empty string.
+func (r FilterRules) match(c Component, licenseIDs []string) string {
+	if shouldIgnorePURL(Filters{FilterRules: r}, c.PURL) {
+		return "purlRegex"
+	}
+
+	if c.Supplier != nil {
+		for _, re := range r.Suppliers {
+			if re.MatchString(c.Supplier.Name) {
+				return fmt.Sprintf("supplier %q", re.String())
+			}
+		}
+	}
+
+	scope := c.Scope
+	if scope == "" {
+		scope = defaultScope
+	}
+
+	for _, s := range r.Scopes {
+		if strings.EqualFold(s, scope) {
+			return fmt.Sprintf("scope %q", s)
+		}
+	}
+
+	for _, t := range r.Types {
+		if c.Type != "" && strings.EqualFold(t, c.Type) {
+			return fmt.Sprintf("type %q", t)
+		}
+	}
+
+	for _, re := range r.Groups {
+		if c.Group != "" && re.MatchString(c.Group) {
+			return fmt.Sprintf("group %q", re.String())
+		}
+	}
+
+	for _, re := range r.Licenses {
+		if slices.ContainsFunc(licenseIDs, re.MatchString) {
+			return fmt.Sprintf("license %q", re.String())
+		}
+	}
+
+	for _, pf := range r.Properties {
+		for _, p := range c.Properties {
+			if p.Name == pf.Name && (pf.Value == nil || pf.Value.MatchString(p.Value)) {
+				return fmt.Sprintf("property %q", pf.Name)
+			}
+		}
+	}
+
+	return ""
+}
+
+// filterReport records the components removed by each filter rule.
+type filterReport map[string][]string
+
+func (r filterReport) add(rule string, c Component) {
+	name := c.PURL
+	if name == "" {
+		name = c.Name + "@" + c.Version
+	}
+
+	r[rule] = append(r[rule], name)
+}
+
+// log logs the components removed by each rule. The first-party exclusions are
+// warnings: a namespace or a supplier can be shared with third-party
+// components, which are then kept with an include rule.
+func (r filterReport) log() {
+	rules := make([]string, 0, len(r))
+	for rule := range r {
+		rules = append(rules, rule)
+	}
+
+	sort.Strings(rules)
+
+	for _, rule := range rules {
+		if strings.HasPrefix(rule, "first-party ") {
+			log.Warn().
+				Str("rule", rule).
+				Int("count", len(r[rule])).
+				Strs("components", r[rule]).
+				Msg("Components excluded as first party, add an include filter for the third-party ones.")
+
+			continue
+		}
+
+		log.Info().
+			Str("rule", rule).
+			Int("count", len(r[rule])).
+			Strs("components", r[rule]).
+			Msg("Components excluded by filter.")
+	}
+}
@@ -0,0 +1,154 @@
+package generator
+
+import (
+	"encoding/json"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExcludedBy(t *testing.T) {
+	t.Parallel()
+
+	filters := Filters{FilterRules: FilterRules{
+		Scopes:   []string{"excluded", "optional"},
+		Types:    []string{"file", "container"},
+		Groups:   []*regexp.Regexp{regexp.MustCompile(`^com\.example`)},
+		Licenses: []*regexp.Regexp{regexp.MustCompile(`^LicenseRef-Proprietary$`)},
+		Properties: []PropertyFilter{
+			{Name: "cdx:npm:package:development", Value: regexp.MustCompile(`^true$`)},
+			{Name: "internal"},
+		},
+	}}
+
+	testCases := []struct {
+		desc       string
+		component  Component
+		licenseIDs []string
+		expected   string
+	}{
+		{
+			desc:      "default scope is kept",
+			component: Component{Type: "library", Name: "lodash"},
+		},
+		{
+			desc:      "excluded scope",
+			component: Component{Name: "jest", Scope: "Excluded"},
+			expected:  `scope "excluded"`,
+		},
+		{
+			desc:      "optional scope",
+			component: Component{Name: "fsevents", Scope: "optional"},
+			expected:  `scope "optional"`,
+		},
+		{
+			desc:      "type",
+			component: Component{Type: "file", Name: "main.js"},
+			expected:  `type "file"`,
+		},
+		{
+			desc:      "group",
+			component: Component{Group: "com.example.internal", Name: "lib"},
+			expected:  `group "^com\\.example"`,
+		},
+		{
+			desc:       "license",
+			component:  Component{Name: "lib"},
+			licenseIDs: []string{"MIT", "LicenseRef-Proprietary"},
+			expected:   `license "^LicenseRef-Proprietary$"`,
+		},
+		{
+			desc: "property value",
+			component: Component{Name: "eslint", Properties: []Property{
+				{Name: "cdx:npm:package:development", Value: "true"},
+			}},
+			expected: `property "cdx:npm:package:development"`,
+		},
+		{
+			desc: "property value not matching",
+			component: Component{Name: "react", Properties: []Property{
+				{Name: "cdx:npm:package:development", Value: "false"},
+			}},
+		},
+		{
+			desc: "property with any value",
+			component: Component{Name: "tool", Properties: []Property{
+				{Name: "internal", Value: "yes"},
+			}},
+			expected: `property "internal"`,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, test.expected, excludedBy(test.component, test.licenseIDs, filters))
+		})
+	}
+}
+
+func TestExcludedBy_Include(t *testing.T) {
+	t.Parallel()
+
+	filters := Filters{
+		FilterRules: FilterRules{
+			Scopes: []string{"excluded"},
+		},
+		Include: FilterRules{
+			PURLRegex: []*regexp.Regexp{regexp.MustCompile(`^pkg:npm/typescript@`)},
+		},
+	}
+
+	tsc := Component{Name: "typescript", Scope: "excluded", PURL: "pkg:npm/typescript@5.4.0"}
+	jest := Component{Name: "jest", Scope: "excluded", PURL: "pkg:npm/jest@29.0.0"}
+
+	assert.Empty(t, excludedBy(tsc, nil, filters))
+	assert.Equal(t, `scope "excluded"`, excludedBy(jest, nil, filters))
+}
+
+func TestFilters_UnmarshalJSON(t *testing.T) {
+	t.Parallel()
+
+	data := []byte(`{
+		"purlRegex": ["use\\.local"],
+		"scopes": ["excluded"],
+		"properties": [{"name": "cdx:npm:package:development", "value": "^true$"}],
+		"include": {"suppliers": ["^Traefik Labs$"]}
+	}`)
+
+	var filters Filters
+	require.NoError(t, json.Unmarshal(data, &filters))
+
+	require.Len(t, filters.PURLRegex, 1)
+	assert.Equal(t, []string{"excluded"}, filters.Scopes)
+	require.Len(t, filters.Properties, 1)
+	assert.Equal(t, "cdx:npm:package:development", filters.Properties[0].Name)
+	assert.True(t, filters.Properties[0].Value.MatchString("true"))
+	require.Len(t, filters.Include.Suppliers, 1)
+	assert.Empty(t, filters.Include.PURLRegex)
+}
+
+func TestBuildIndex_Filters(t *testing.T) {
+	t.Parallel()
+
+	components := []Component{
+		{Name: "react", Version: "18.0.0", PURL: "pkg:npm/react@18.0.0", Licenses: []LicenseChoice{{License: &License{ID: "MIT"}}}},
+		{Name: "jest", Version: "29.0.0", PURL: "pkg:npm/jest@29.0.0", Scope: "excluded", Licenses: []LicenseChoice{{License: &License{ID: "MIT"}}}},
+		{Name: "blob", Version: "1.0.0", PURL: "pkg:npm/blob@1.0.0", Licenses: []LicenseChoice{{License: &License{ID: "BUSL-1.1"}}}},
+	}
+
+	filters := Filters{FilterRules: FilterRules{
+		Scopes:   []string{"excluded"},
+		Licenses: []*regexp.Regexp{regexp.MustCompile(`^BUSL-`)},
+	}}
+
+	byLicense, byKey := buildIndex(components, inputs{filters: filters}, copyrightEnricher{}, nil)
+
+	assert.Len(t, byKey, 1)
+	assert.Contains(t, byKey, "pkg:npm/react@18.0.0")
+	assert.Len(t, byLicense["MIT"], 1)
+	assert.NotContains(t, byLicense, "BUSL-1.1")
+}
@@ -0,0 +1,245 @@
+package generator
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+)
+
+// firstParty identifies the components of the project itself, which must not
+// be attributed.
+type firstParty struct {
+	rules     []firstPartyRule
+	suppliers []string
+}
+
+// firstPartyRule matches the components whose PURL falls under base, e.g.
+// "pkg:golang/github.com/traefik/traefik/v3" or "pkg:npm/@traefik". When
+// prefix is set, it is matched as a plain string prefix of the decoded PURL
+// (when it starts with "pkg:") or of the package name instead.
+type firstPartyRule struct {
+	base   string
+	prefix string
+	origin string
+}
+
+// detectFirstParty infers the first-party components from the SBOM subject,
+// the go.mod of the working directory, the configured package.json files, and
+// the configured prefixes.
+func detectFirstParty(cfg Config, sbom SBOM) firstParty {
+	var fp firstParty
+
+	for _, prefix := range cfg.FirstPartyPrefixes {
+		if prefix = strings.TrimSpace(prefix); prefix != "" {
+			fp.rules = append(fp.rules, firstPartyRule{prefix: prefix, origin: "configured prefix"})
+		}
+	}
+
+	if cfg.DisableFirstPartyDetection {
+		return fp
+	}
+
+	if c := sbom.Metadata.Component; c != nil {
+		if base := firstPartyBase(c.PURL, cfg.FirstPartyNamespaces); base != "" {
+			fp.rules = append(fp.rules, firstPartyRule{base: base, origin: "SBOM metadata component"})
+		}
+
+		// A namespace or a supplier can be shared with third-party components,
+		// e.g. the org.apache Maven group: they are only matched on demand.
+		if cfg.FirstPartyNamespaces && c.Supplier != nil {
+			fp.addSupplier(c.Supplier.Name)
+		}
+	}
+
+	if cfg.FirstPartyNamespaces && sbom.Metadata.Supplier != nil {
+		fp.addSupplier(sbom.Metadata.Supplier.Name)
+	}
+
+	module, replaced := readGoMod("go.mod")
+	if module != "" {
+		fp.rules = append(fp.rules, firstPartyRule{base: "pkg:golang/" + module, origin: "go.mod module"})
+	}
+
+	for _, path := range replaced {
+		fp.rules = append(fp.rules, firstPartyRule{base: "pkg:golang/" + path, origin: "go.mod local replacement"})
+	}
+
+	for _, filename := range cfg.FirstPartyPackageJSONs {
+		if base := packageJSONBase(filename); base != "" {
+			fp.rules = append(fp.rules, firstPartyRule{base: base, origin: filename + " name"})
+		}
+	}
+
+	for _, r := range fp.rules {
+		log.Info().
+			Str("origin", r.origin).
+			Str("match", r.base+r.prefix).
+			Msg("First-party components detected.")
+	}
+
+	for _, s := range fp.suppliers {
+		log.Info().
+			Str("origin", "SBOM metadata supplier").
+			Str("match", s).
+			Msg("First-party components detected.")
+	}
+
+	return fp
+}
+
+func (fp *firstParty) addSupplier(name string) {
+	if name = strings.TrimSpace(name); name != "" {
+		fp.suppliers = append(fp.suppliers, name)
+	}
+}
+
+// match returns a description of the rule identifying the component as first
+// party, or an empty string.
+func (fp firstParty) match(c Component) string {
+	if p, ok := parsePURL(c.PURL); ok {
+		base := p.base()
+
+		for _, r := range fp.rules {
+			if r.matches(p, base) {
+				return "first-party " + r.origin + " " + strconv.Quote(r.base+r.prefix)
+			}
+		}
+	}
+
+	if c.Supplier != nil {
+		for _, s := range fp.suppliers {
+			if strings.EqualFold(s, strings.TrimSpace(c.Supplier.Name)) {
+				return "first-party supplier " + strconv.Quote(s)
+			}
+		}
+	}
+
+	return ""
+}
+
+func (r firstPartyRule) matches(p packageURL, base string) bool {
+	if r.prefix != "" {
+		if strings.HasPrefix(r.prefix, "pkg:") {
+			return strings.HasPrefix(base, r.prefix)
+		}
+
+		return strings.HasPrefix(p.fullName(), r.prefix)
+	}
+
+	return base == r.base || strings.HasPrefix(base, r.base+"/")
+}
+
+// firstPartyBase returns the PURL prefix shared by the components of the
+// project identified by purl: the module path for Go, the package itself
+// otherwise, or its namespace (npm scope, Maven group, etc.) when namespace is
+// set and it has one.
+func firstPartyBase(purl string, namespace bool) string {
+	p, ok := parsePURL(purl)
+	if !ok {
+		return ""
+	}
+
+	if !namespace || p.Type == "golang" || p.Namespace == "" {
+		return p.base()
+	}
+
+	return "pkg:" + p.Type + "/" + p.Namespace
+}
+
+// readGoMod returns the module path of a go.mod file, and the modules it
+// replaces with local directories.
+func readGoMod(filename string) (string, []string) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return "", nil
+	}
+	defer func() { _ = f.Close() }()
+
+	var (
+		module   string
+		replaced []string
+		inBlock  bool
+	)
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line, _, _ := strings.Cut(scanner.Text(), "//")
+		line = strings.TrimSpace(line)
+
+		switch {
+		case inBlock && line == ")":
+			inBlock = false
+		case inBlock:
+			if path := localReplacement(line); path != "" {
+				replaced = append(replaced, path)
+			}
+		case line == "replace (":
+			inBlock = true
+		case strings.HasPrefix(line, "replace "):
+			if path := localReplacement(strings.TrimPrefix(line, "replace ")); path != "" {
+				replaced = append(replaced, path)
+			}
+		case strings.HasPrefix(line, "module "):
+			module = unquoteGoModPath(strings.TrimPrefix(line, "module "))
+		}
+	}
+
+	return module, replaced
+}
+
+// localReplacement returns the module path of a replace directive, such as
+// "example.com/foo v1.0.0 => ./foo", when it points to a local directory.
+func localReplacement(directive string) string {
+	old, target, ok := strings.Cut(directive, "=>")
+	if !ok {
+		return ""
+	}
+
+	target = strings.TrimSpace(target)
+	if !strings.HasPrefix(target, "./") && !strings.HasPrefix(target, "../") && !filepath.IsAbs(target) {
+		return ""
+	}
+
+	fields := strings.Fields(old)
+	if len(fields) == 0 {
+		return ""
+	}
+
+	return unquoteGoModPath(fields[0])
+}
+
+func unquoteGoModPath(s string) string {
+	s = strings.TrimSpace(s)
+	if u, err := strconv.Unquote(s); err == nil {
+		return u
+	}
+
+	return s
+}
+
+// packageJSONBase returns the PURL prefix of the packages published by the
+// project described by a package.json: its scope, or the package itself.
+func packageJSONBase(filename string) string {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return ""
+	}
+
+	var pkg struct {
+		Name string `json:"name"`
+	}
+	if err := json.Unmarshal(data, &pkg); err != nil || pkg.Name == "" {
+		return ""
+	}
+
+	if scope, _, ok := strings.Cut(pkg.Name, "/"); ok && strings.HasPrefix(scope, "@") {
+		return "pkg:npm/" + scope
+	}
+
+	return "pkg:npm/" + pkg.Name
+}
@@ -0,0 +1,216 @@
+package generator
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFirstPartyBase(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		purl      string
+		namespace bool
+		expected  string
+	}{
+		{purl: "pkg:golang/github.com/traefik/traefik/v3@v3.1.0?type=module", expected: "pkg:golang/github.com/traefik/traefik/v3"},
+		{purl: "pkg:golang/github.com/traefik/traefik/v3@v3.1.0?type=module", namespace: true, expected: "pkg:golang/github.com/traefik/traefik/v3"},
+		{purl: "pkg:npm/%40traefik/ui@1.0.0", expected: "pkg:npm/@traefik/ui"},
+		{purl: "pkg:npm/%40traefik/ui@1.0.0", namespace: true, expected: "pkg:npm/@traefik"},
+		{purl: "pkg:npm/webui@1.0.0", namespace: true, expected: "pkg:npm/webui"},
+		{purl: "pkg:maven/com.example/app@1.0.0", expected: "pkg:maven/com.example/app"},
+		{purl: "pkg:maven/com.example/app@1.0.0", namespace: true, expected: "pkg:maven/com.example"},
+		{purl: "not a purl", expected: ""},
+	}
+
+	for _, test := range testCases {
+		t.Run(fmt.Sprintf("%s namespace=%t", test.purl, test.namespace), func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, test.expected, firstPartyBase(test.purl, test.namespace))
+		})
+	}
+}
+
+func TestReadGoMod(t *testing.T) {
+	t.Parallel()
+
+	filename := filepath.Join(t.TempDir(), "go.mod")
+	content := `module github.com/traefik/traefik/v3 // main module
+
+go 1.24
+
+require github.com/foo/bar v1.0.0
+
+replace github.com/abbot/go-http-auth => github.com/containous/go-http-auth v0.4.1-0.20200324110947-a37a7636d23e
+
+replace (
+	use.local/plugin v0.0.0 => ./plugins/plugin
+	"example.com/shared" => ../shared
+)
+`
+	require.NoError(t, os.WriteFile(filename, []byte(content), 0o644))
+
+	module, replaced := readGoMod(filename)
+
+	assert.Equal(t, "github.com/traefik/traefik/v3", module)
+	assert.Equal(t, []string{"use.local/plugin", "example.com/shared"}, replaced)
+}
+
+func TestPackageJSONBase(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+
+	scoped := filepath.Join(dir, "scoped.json")
+	require.NoError(t, os.WriteFile(scoped, []byte(`{"name": "@traefik/ui", "version": "1.0.0"}`), 0o644))
+
+	unscoped := filepath.Join(dir, "unscoped.json")
+	require.NoError(t, os.WriteFile(unscoped, []byte(`{"name": "traefik-webui"}`), 0o644))
+
+	assert.Equal(t, "pkg:npm/@traefik", packageJSONBase(scoped))
+	assert.Equal(t, "pkg:npm/traefik-webui", packageJSONBase(unscoped))
+	assert.Empty(t, packageJSONBase(filepath.Join(dir, "missing.json")))
+}
+
+func TestFirstParty_Match(t *testing.T) {
+	t.Parallel()
+
+	sbom := SBOM{Metadata: Metadata{Component: &Component{
+		PURL:     "pkg:golang/github.com/traefik/traefik/v3@v3.1.0",
+		Supplier: &OrganizationalEntity{Name: "Traefik Labs"},
+	}}}
+
+	fp := detectFirstParty(Config{FirstPartyPrefixes: []string{"@traefik/", "pkg:maven/com.example"}, FirstPartyNamespaces: true}, sbom)
+
+	testCases := []struct {
+		desc      string
+		component Component
+		expected  string
+	}{
+		{
+			desc:      "main module package",
+			component: Component{PURL: "pkg:golang/github.com/traefik/traefik/v3/pkg/config@v3.1.0"},
+			expected:  `first-party SBOM metadata component "pkg:golang/github.com/traefik/traefik/v3"`,
+		},
+		{
+			desc:      "sibling module",
+			component: Component{PURL: "pkg:golang/github.com/traefik/traefik/v3beta@v3.1.0"},
+		},
+		{
+			desc:      "configured name prefix",
+			component: Component{PURL: "pkg:npm/%40traefik/ui@1.0.0"},
+			expected:  `first-party configured prefix "@traefik/"`,
+		},
+		{
+			desc:      "configured PURL prefix",
+			component: Component{PURL: "pkg:maven/com.example.internal/lib@1.0.0"},
+			expected:  `first-party configured prefix "pkg:maven/com.example"`,
+		},
+		{
+			desc:      "supplier",
+			component: Component{PURL: "pkg:npm/foo@1.0.0", Supplier: &OrganizationalEntity{Name: "traefik labs"}},
+			expected:  `first-party supplier "Traefik Labs"`,
+		},
+		{
+			desc:      "third party",
+			component: Component{PURL: "pkg:golang/github.com/foo/bar@v1.0.0", Supplier: &OrganizationalEntity{Name: "Foo"}},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, test.expected, fp.match(test.component))
+		})
+	}
+}
+
+func TestDetectFirstParty_ExactPackageByDefault(t *testing.T) {
+	t.Parallel()
+
+	sbom := SBOM{Metadata: Metadata{
+		Component: &Component{PURL: "pkg:maven/org.apache/myapp@1.0.0", Supplier: &OrganizationalEntity{Name: "Apache"}},
+		Supplier:  &OrganizationalEntity{Name: "Apache"},
+	}}
+
+	fp := detectFirstParty(Config{}, sbom)
+
+	assert.Equal(t, `first-party SBOM metadata component "pkg:maven/org.apache/myapp"`, fp.match(Component{PURL: "pkg:maven/org.apache/myapp@1.0.0"}))
+	assert.Empty(t, fp.match(Component{PURL: "pkg:maven/org.apache/commons-lang3@3.14.0"}))
+	assert.Empty(t, fp.match(Component{PURL: "pkg:maven/org.example/lib@1.0.0", Supplier: &OrganizationalEntity{Name: "Apache"}}))
+}
+
+func TestDetectFirstParty_Disabled(t *testing.T) {
+	t.Parallel()
+
+	sbom := SBOM{Metadata: Metadata{Component: &Component{PURL: "pkg:npm/webui@1.0.0"}}}
+
+	fp := detectFirstParty(Config{DisableFirstPartyDetection: true, FirstPartyPrefixes: []string{"pkg:npm/@traefik/"}}, sbom)
+
+	assert.Empty(t, fp.match(Component{PURL: "pkg:npm/webui@1.0.0"}))
+	assert.NotEmpty(t, fp.match(Component{PURL: "pkg:npm/@traefik/ui@1.0.0"}))
+}
+
+func TestExcludedBy_FirstPartyInclude(t *testing.T) {
+	t.Parallel()
+
+	filters := Filters{firstParty: firstParty{suppliers: []string{"Traefik Labs"}}}
+
+	c := Component{PURL: "pkg:npm/foo@1.0.0", Supplier: &OrganizationalEntity{Name: "Traefik Labs"}}
+	assert.Equal(t, `first-party supplier "Traefik Labs"`, excludedBy(c, nil, filters))
+
+	filters.Include.Suppliers = []*regexp.Regexp{regexp.MustCompile("^Traefik Labs$")}
+	assert.Empty(t, excludedBy(c, nil, filters))
+}
+
+func TestDetectFirstParty_PackageJSONs(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	webui := filepath.Join(dir, "webui", "package.json")
+	require.NoError(t, os.MkdirAll(filepath.Dir(webui), 0o755))
+	require.NoError(t, os.WriteFile(webui, []byte(`{"name": "@traefik/webui"}`), 0o644))
+
+	fp := detectFirstParty(Config{FirstPartyPackageJSONs: []string{filepath.Join(dir, "package.json"), webui}}, SBOM{})
+
+	assert.Equal(t, `first-party `+webui+` name "pkg:npm/@traefik"`, fp.match(Component{PURL: "pkg:npm/%40traefik/ui@1.0.0"}))
+	assert.Empty(t, fp.match(Component{PURL: "pkg:npm/webui@1.0.0"}))
+}
+
+func TestBuildIndex_FirstPartyNamespaceInclude(t *testing.T) {
+	t.Parallel()
+
+	sbom := SBOM{Metadata: Metadata{Component: &Component{PURL: "pkg:maven/org.apache/myapp@1.0.0"}}}
+
+	components := []Component{
+		{Name: "myapp-core", Version: "1.0.0", PURL: "pkg:maven/org.apache/myapp-core@1.0.0", Licenses: []LicenseChoice{{License: &License{ID: "Apache-2.0"}}}},
+		{Name: "commons-lang3", Version: "3.14.0", PURL: "pkg:maven/org.apache/commons-lang3@3.14.0", Licenses: []LicenseChoice{{License: &License{ID: "Apache-2.0"}}}},
+	}
+
+	// By default, only the project itself is first party.
+	filters := Filters{firstParty: detectFirstParty(Config{}, sbom)}
+
+	_, byKey := buildIndex(components, inputs{filters: filters}, copyrightEnricher{}, nil)
+	assert.Len(t, byKey, 2)
+
+	filters = Filters{firstParty: detectFirstParty(Config{FirstPartyNamespaces: true}, sbom)}
+
+	_, byKey = buildIndex(components, inputs{filters: filters}, copyrightEnricher{}, nil)
+	assert.Empty(t, byKey)
+
+	// A third-party package sharing the namespace of the project is kept with an
+	// include rule.
+	filters.Include.PURLRegex = []*regexp.Regexp{regexp.MustCompile(`^pkg:maven/org\.apache/commons-`)}
+
+	_, byKey = buildIndex(components, inputs{filters: filters}, copyrightEnricher{}, nil)
+	assert.NotContains(t, byKey, "pkg:maven/org.apache/myapp-core@1.0.0")
+	assert.Contains(t, byKey, "pkg:maven/org.apache/commons-lang3@3.14.0")
+}
@@ -0,0 +1,230 @@
+package generator
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// templateFuncs returns the functions available to the HTML and text
+// templates.
+func templateFuncs() map[string]any {
+	return map[string]any{
+		"join":             join,
+		"lower":            strings.ToLower,
+		"upper":            strings.ToUpper,
+		"trim":             strings.TrimSpace,
+		"escapeMarkdown":   escapeMarkdown,
+		"slugify":          slugify,
+		"formatDate":       formatDate,
+		"ecosystem":        ecosystem,
+		"groupByEcosystem": groupByEcosystem,
+		"toJSON":           toJSON,
+		"wrap":             wrap,
+		"indent":           indent,
+		"repeat":           repeat,
+		"csv":              csvRecord(','),
+		"tsv":              csvRecord('\t'),
+	}
+}
+
+// join joins items with sep. The separator comes first so that items can be
+// piped: {{.LicenseIDs | join ", "}}.
+func join(sep string, items []string) string {
+	return strings.Join(items, sep)
+}
+
+// markdownEscaper escapes the characters having a meaning in Markdown.
+var markdownEscaper = strings.NewReplacer(
+	`\`, `\\`, "`", "\\`", `*`, `\*`, `_`, `\_`, `{`, `\{`, `}`, `\}`, `[`, `\[`, `]`, `\]`,
+	`<`, `\<`, `>`, `\>`, `(`, `\(`, `)`, `\)`, `#`, `\#`, `+`, `\+`, `-`, `\-`, `!`, `\!`,
+	`|`, `\|`, `~`, `\~`,
+)
+
+// escapeMarkdown escapes s so that it renders verbatim in Markdown.
+func escapeMarkdown(s string) string {
+	return markdownEscaper.Replace(s)
+}
+
+// slugify returns s lowercased, with runs of characters other than ASCII
+// letters and digits replaced by "-", e.g. "Apache License 2.0" gives
+// "apache-license-2-0".
+func slugify(s string) string {
+	var b strings.Builder
+
+	dash := false
+
+	for _, r := range strings.ToLower(s) {
+		if r >= 'a' && r <= 'z' || r >= '0' && r <= '9' {
+			if dash && b.Len() > 0 {
+				b.WriteByte('-')
+			}
+
+			b.WriteRune(r)
+
+			dash = false
+
+			continue
+		}
+
+		dash = true
+	}
+
+	return b.String()
+}
+
+// formatDate formats value, a time or an RFC 3339 date such as
+// Model.GeneratedAt, with the Go layout: {{formatDate "2006-01-02" .GeneratedAt}}.
+func formatDate(layout string, value any) (string, error) {
+	switch v := value.(type) {
+	case time.Time:
+		return v.Format(layout), nil
+	case string:
+		t, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			return "", fmt.Errorf("formatDate: %w", err)
+		}
+
+		return t.Format(layout), nil
+	default:
+		return "", fmt.Errorf("formatDate: unsupported value of type %T", value)
+	}
+}
+
+// otherEcosystem is the ecosystem of the components without a valid PURL.
+const otherEcosystem = "other"
+
+// ecosystem returns the type of the PURL, e.g. "npm" or "golang", or "other".
+func ecosystem(purl string) string {
+	p, ok := parsePURL(purl)
+	if !ok {
+		return otherEcosystem
+	}
+
+	return p.Type
+}
+
+// groupByEcosystem groups components by the type of their PURL. Groups are
+// sorted by ecosystem, "other" last, and keep the order of the components.
+func groupByEcosystem(components []OutComponent) []ComponentGroup {
+	var groups []ComponentGroup
+
+	index := map[string]int{}
+
+	for _, c := range components {
+		eco := ecosystem(c.PURL)
+
+		i, ok := index[eco]
+		if !ok {
+			i = len(groups)
+			index[eco] = i
+			groups = append(groups, ComponentGroup{Ecosystem: eco})
+		}
+
+		groups[i].Components = append(groups[i].Components, c)
+	}
+
+	sort.SliceStable(groups, func(i, j int) bool {
+		if (groups[i].Ecosystem == otherEcosystem) != (groups[j].Ecosystem == otherEcosystem) {
+			return groups[j].Ecosystem == otherEcosystem
+		}
+
+		return groups[i].Ecosystem < groups[j].Ecosystem
+	})
+
+	return groups
+}
+
+// toJSON returns value as indented JSON, e.g. {{toJSON .}} for the whole model.
+func toJSON(value any) (string, error) {
+	b, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("toJSON: %w", err)
+	}
+
+	return string(b), nil
+}
+
+// wrap hard-wraps the lines of s longer than width at spaces, keeping their
+// indentation. Words longer than width are not split.
+func wrap(width int, s string) string {
+	lines := strings.Split(s, "\n")
+
+	var out []string
+
+	for _, line := range lines {
+		if len([]rune(line)) <= width {
+			out = append(out, line)
+
+			continue
+		}
+
+		trimmed := strings.TrimLeft(line, " \t")
+		prefix := line[:len(line)-len(trimmed)]
+
+		current := prefix
+
+		for _, word := range strings.Fields(trimmed) {
+			if current != prefix && len([]rune(current))+1+len([]rune(word)) > width {
+				out = append(out, current)
+				current = prefix
+			}
+
+			if current != prefix {
+				current += " "
+			}
+
+			current += word
+		}
+
+		out = append(out, current)
+	}
+
+	return strings.Join(out, "\n")
+}
+
+// indent prefixes the non-empty lines of s with n spaces.
+func indent(n int, s string) string {
+	pad := strings.Repeat(" ", n)
+
+	lines := strings.Split(s, "\n")
+	for i, line := range lines {
+		if strings.TrimSpace(line) != "" {
+			lines[i] = pad + line
+		}
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// repeat returns s repeated n times, e.g. {{repeat "=" 80}} for a separator.
+func repeat(s string, n int) string {
+	return strings.Repeat(s, max(n, 0))
+}
+
+// csvRecord returns a function encoding its arguments as a CSV record, without
+// the line break, quoting the fields as needed: {{csv .Name .Version}}.
+func csvRecord(comma rune) func(fields ...string) (string, error) {
+	return func(fields ...string) (string, error) {
+		var b bytes.Buffer
+
+		w := csv.NewWriter(&b)
+		w.Comma = comma
+
+		if err := w.Write(fields); err != nil {
+			return "", fmt.Errorf("csv: %w", err)
+		}
+
+		w.Flush()
+
+		if err := w.Error(); err != nil {
+			return "", fmt.Errorf("csv: %w", err)
+		}
+
+		return strings.TrimSuffix(b.String(), "\n"), nil
+	}
+}
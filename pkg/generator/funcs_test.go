@@ -0,0 +1,110 @@
+package generator
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEscapeMarkdown(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, `\*\*bold\*\* \[link\]\(url\) a\_b \#1 \|`, escapeMarkdown("**bold** [link](url) a_b #1 |"))
+	assert.Equal(t, "plain text.", escapeMarkdown("plain text."))
+}
+
+func TestSlugify(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]string{
+		"Apache License 2.0":    "apache-license-2-0",
+		"  BSD-3-Clause  ":      "bsd-3-clause",
+		"LicenseRef-Custom (X)": "licenseref-custom-x",
+		"@traefik/ui":           "traefik-ui",
+		"":                      "",
+	}
+
+	for in, expected := range testCases {
+		assert.Equal(t, expected, slugify(in), in)
+	}
+}
+
+func TestFormatDate(t *testing.T) {
+	t.Parallel()
+
+	got, err := formatDate("2006-01-02", "2026-03-04T05:06:07Z")
+	require.NoError(t, err)
+	assert.Equal(t, "2026-03-04", got)
+
+	got, err = formatDate("January 2, 2006", time.Date(2026, time.March, 4, 0, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	assert.Equal(t, "March 4, 2026", got)
+
+	_, err = formatDate("2006", "yesterday")
+	require.Error(t, err)
+
+	_, err = formatDate("2006", 42)
+	require.Error(t, err)
+}
+
+func TestGroupByEcosystem(t *testing.T) {
+	t.Parallel()
+
+	components := []OutComponent{
+		{Name: "b", PURL: "pkg:npm/b@1.0.0"},
+		{Name: "font"},
+		{Name: "x", PURL: "pkg:golang/example.com/x@v1.0.0"},
+		{Name: "a", PURL: "pkg:npm/a@1.0.0"},
+	}
+
+	groups := groupByEcosystem(components)
+
+	require.Len(t, groups, 3)
+	assert.Equal(t, "golang", groups[0].Ecosystem)
+	assert.Equal(t, "npm", groups[1].Ecosystem)
+	assert.Equal(t, []OutComponent{components[0], components[3]}, groups[1].Components)
+	assert.Equal(t, otherEcosystem, groups[2].Ecosystem)
+}
+
+func TestJoin(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "MIT, ISC", join(", ", []string{"MIT", "ISC"}))
+	assert.Empty(t, join(", ", nil))
+}
+
+func TestWrap(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "short line", wrap(20, "short line"))
+	assert.Equal(t, "the quick brown\nfox jumps over\nthe lazy dog", wrap(15, "the quick brown fox jumps over the lazy dog"))
+	assert.Equal(t, "  the quick\n  brown fox\n\nend", wrap(12, "  the quick brown fox\n\nend"))
+	assert.Equal(t, "a\nverylongword\nb", wrap(5, "a verylongword b"))
+}
+
+func TestIndent(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "  a\n\n  b", indent(2, "a\n\nb"))
+}
+
+func TestRepeat(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "===", repeat("=", 3))
+	assert.Empty(t, repeat("=", -1))
+}
+
+func TestCSVRecord(t *testing.T) {
+	t.Parallel()
+
+	got, err := csvRecord(',')("foo", "MIT, ISC", `say "hi"`, "a\nb")
+	require.NoError(t, err)
+	assert.Equal(t, "foo,\"MIT, ISC\",\"say \"\"hi\"\"\",\"a\nb\"", got)
+
+	got, err = csvRecord('\t')("foo", "MIT, ISC")
+	require.NoError(t, err)
+	assert.Equal(t, "foo\tMIT, ISC", got)
+}
@@ -3,12 +3,16 @@ package generator
 import (
 	"context"
 	"embed"
+	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
 	"sort"
 	"strings"
 	"time"
+
+	"github.com/rs/zerolog/log"
 )
 
 //go:embed templates/*.gotpl data/*.json
@@ -34,7 +38,12 @@ func (e UnknownLicensesError) Error() string {
 
 // Run executes the generator with the given configuration.
 func Run(ctx context.Context, cfg Config) error {
-	sbom, excludeComponents, licenseMap, licenseCorrections, spdxNames, err := loadInputs(ctx, cfg)
+	outputs := cfg.outputs()
+	if err := validateOutputs(outputs); err != nil {
+		return fmt.Errorf("invalid outputs: %w", err)
+	}
+
+	in, err := loadInputs(ctx, cfg)
 	if err != nil {
 		return fmt.Errorf("failed to load inputs: %w", err)
 	}
@@ -47,120 +56,180 @@ func Run(ctx context.Context, cfg Config) error {
 		return fmt.Errorf("failed to create output licenses directory: %w", err)
 	}
 
-	model, err := buildModel(ctx, cfg, sbom, excludeComponents, licenseMap, licenseCorrections, spdxNames)
+	model, err := buildModel(ctx, cfg, in)
 	if err != nil {
 		return fmt.Errorf("failed to build model: %w", err)
 	}
 
-	htmlOut, err := renderHTML(cfg, embedded, model)
-	if err != nil {
-		return fmt.Errorf("failed to render HTML output: %w", err)
-	}
+	written := make([]string, 0, len(outputs))
 
-	noticeOut, err := renderText(cfg, embedded, model)
-	if err != nil {
-		return fmt.Errorf("failed to render notice output: %w", err)
-	}
+	for _, o := range outputs {
+		var out string
 
-	tpnDir := filepath.Join(cfg.OutDir, cfg.HTMLFileName)
-	nDir := filepath.Join(cfg.OutDir, cfg.NoticeFileName)
+		if write, ok := builtinWriters[o.Template]; ok {
+			out, err = write(in, model)
+		} else {
+			out, err = renderOutput(o, cfg.TemplateDir, embedded, model)
+		}
 
-	if err := writeText(tpnDir, htmlOut); err != nil {
-		return fmt.Errorf("failed to write HTML output: %w", err)
+		if err != nil {
+			return fmt.Errorf("failed to render %s: %w", o.Path, err)
+		}
+
+		path := o.path(cfg.OutDir)
+		if err := writeText(path, out); err != nil {
+			return fmt.Errorf("failed to write %s: %w", o.Path, err)
+		}
+
+		written = append(written, path)
 	}
 
-	if err := writeText(nDir, noticeOut); err != nil {
-		return fmt.Errorf("failed to write notice output: %w", err)
+	fmt.Println("Wrote:")
+
+	for _, path := range written {
+		fmt.Printf("- %s\n", path)
 	}
 
-	fmt.Printf("Wrote:\n- %s\n- %s\n- %s/\n",
-		tpnDir,
-		nDir,
-		cfg.OutLicensesDir,
-	)
+	fmt.Printf("- %s/\n", cfg.OutLicensesDir)
 
 	return nil
 }
 
-// readJSONData reads a JSON file from an external path if provided, or falls back to the embedded default.
-func readJSONData[T any](externalPath, embeddedPath string) (T, error) {
-	if externalPath != "" {
-		return readJSON[T](os.ReadFile, externalPath)
-	}
-
-	return readJSON[T](embedded.ReadFile, embeddedPath)
+// inputs holds the SBOM and the data the model is built from.
+type inputs struct {
+	sbom SBOM
+	// sbomJSON is the SBOM file, from which the enriched CycloneDX SBOM keeps
+	// the fields assimilis does not use.
+	sbomJSON           []byte
+	filters            Filters
+	licenseMap         map[string]string
+	licenseCorrections map[string]LicenseCorrections
+	curations          map[string]Curation
+	extraComponents    []Component
+	spdxNames          map[string]string
 }
 
-func loadInputs(ctx context.Context, cfg Config) (SBOM, Filters, map[string]string, map[string]string, map[string]string, error) {
-	sbom, err := readJSON[SBOM](os.ReadFile, filepath.Join(cfg.SBOMPath, cfg.RepoName+".cdx.json"))
+func loadInputs(ctx context.Context, cfg Config) (inputs, error) {
+	var in inputs
+
+	var err error
+
+	sbomPath := filepath.Join(cfg.SBOMPath, cfg.RepoName+".cdx.json")
+
+	in.sbomJSON, err = os.ReadFile(sbomPath)
 	if err != nil {
-		return SBOM{}, Filters{}, nil, nil, nil, fmt.Errorf("failed to read SBOM: %w", err)
+		return inputs{}, fmt.Errorf("failed to read SBOM: %w", err)
+	}
+
+	if err = json.Unmarshal(in.sbomJSON, &in.sbom); err != nil {
+		return inputs{}, fmt.Errorf("failed to unmarshal SBOM %q: %w", sbomPath, err)
 	}
 
-	filters, err := readJSONData[Filters](cfg.FiltersPath, embeddedFiltersPath)
+	in.filters, err = loadFilters(cfg)
 	if err != nil {
-		return SBOM{}, Filters{}, nil, nil, nil, fmt.Errorf("failed to read filters: %w", err)
+		return inputs{}, fmt.Errorf("failed to read filters: %w", err)
 	}
 
-	licenseMap, err := readJSONData[map[string]string](cfg.LicenseMapPath, embeddedLicenseMapPath)
+	in.licenseMap, err = loadDataMap("license map", embeddedLicenseMapPath, cfg.OrgDataDir, cfg.LicenseMapPath, cfg.LicenseMap, isBlank)
 	if err != nil {
-		return SBOM{}, Filters{}, nil, nil, nil, fmt.Errorf("failed to read license map: %w", err)
+		return inputs{}, fmt.Errorf("failed to read license map: %w", err)
 	}
 
-	licenseCorrections, err := readJSONData[map[string]string](cfg.LicenseCorrectionsPath, embeddedLicenseCorrectionsPath)
+	in.licenseCorrections, err = loadDataMap("license corrections", embeddedLicenseCorrectionsPath, cfg.OrgDataDir, cfg.LicenseCorrectionsPath, cfg.LicenseCorrections, hasNoCorrection)
 	if err != nil {
-		return SBOM{}, Filters{}, nil, nil, nil, fmt.Errorf("failed to read license corrections: %w", err)
+		return inputs{}, fmt.Errorf("failed to read license corrections: %w", err)
 	}
 
-	spdxNames, err := loadSpdxNameMap(ctx, cfg.SPDXVersion)
+	if err := validateLicenseCorrections(in.licenseCorrections); err != nil {
+		return inputs{}, fmt.Errorf("invalid license corrections: %w", err)
+	}
+
+	in.curations, err = loadDataMap("curations", embeddedCurationsPath, cfg.OrgDataDir, cfg.CurationsPath, cfg.Curations, Curation.isEmpty)
 	if err != nil {
-		return SBOM{}, Filters{}, nil, nil, nil, fmt.Errorf("failed to load SPDX names: %w", err)
+		return inputs{}, fmt.Errorf("failed to read curations: %w", err)
 	}
 
-	return sbom, filters, licenseMap, licenseCorrections, spdxNames, nil
-}
+	if err := validateCurations(in.curations); err != nil {
+		return inputs{}, fmt.Errorf("invalid curations: %w", err)
+	}
 
-func shouldIgnoreComponent(c Component, filters Filters) bool {
-	if shouldIgnorePURL(filters, c.PURL) {
-		return true
+	in.extraComponents, err = loadExtraComponents(cfg)
+	if err != nil {
+		return inputs{}, fmt.Errorf("failed to read extra components: %w", err)
 	}
 
-	if c.Supplier != nil {
-		for _, re := range filters.Suppliers {
-			if re.MatchString(c.Supplier.Name) {
-				return true
-			}
-		}
+	in.spdxNames, err = loadSpdxNameMap(ctx, cfg.SPDXVersion)
+	if err != nil {
+		return inputs{}, fmt.Errorf("failed to load SPDX names: %w", err)
 	}
 
-	return false
+	return in, nil
 }
 
-func buildModel(ctx context.Context, cfg Config, sbom SBOM, filters Filters, licenseMap, licenseCorrections, spdxNames map[string]string) (Model, error) {
+func buildModel(ctx context.Context, cfg Config, in inputs) (Model, error) {
 	enricher := newCopyrightEnricher(cfg)
-	byLicense, byKey := buildIndex(sbom.Components, filters, licenseMap, licenseCorrections, enricher)
+	detector := newLicenseDetector(cfg, in.licenseMap)
+
+	in.filters.firstParty = detectFirstParty(cfg, in.sbom)
+
+	components := make([]Component, len(in.sbom.Components), len(in.sbom.Components)+len(in.extraComponents))
+	for i, c := range in.sbom.Components {
+		c.Licenses = selectLicenses(c.Licenses, cfg.PreferDeclaredLicenses)
+		components[i] = c
+	}
+
+	// Extra components go through the same filters, corrections and curations
+	// as the SBOM ones.
+	components = append(components, in.extraComponents...)
 
-	licenses, err := buildLicenseBlocks(ctx, cfg, byLicense, spdxNames)
+	byLicense, byKey := buildIndex(components, in, enricher, detector)
+
+	var textOf componentTextFunc
+	switch {
+	case cfg.ComponentLicenseTexts:
+		textOf = newComponentTextFunc(enricher, detector)
+	case len(in.extraComponents) > 0:
+		textOf = newExtraComponentTextFunc(in.extraComponents)
+	}
+
+	licenses, err := buildLicenseBlocks(ctx, cfg, byLicense, in.spdxNames, textOf)
 	if err != nil {
 		return Model{}, fmt.Errorf("failed to build license blocks: %w", err)
 	}
 
 	overview := buildOverview(licenses)
 
-	notices := buildNotices(byKey)
-
 	return Model{
+		RepoName:    cfg.RepoName,
 		GeneratedAt: time.Now().UTC().Format(time.RFC3339),
 		Overview:    overview,
 		Licenses:    licenses,
-		Notices:     notices,
+		Notices:     buildNotices(byKey),
+		Components:  buildComponents(byKey),
+		byKey:       byKey,
 	}, nil
 }
 
+// buildOverview counts the components using each license. Variants of the same
+// license are counted together and linked through the anchor of the first one.
 func buildOverview(licenses []LicenseBlock) []OverviewItem {
 	overview := make([]OverviewItem, 0, len(licenses))
+	byID := map[string]int{}
+
 	for _, l := range licenses {
-		overview = append(overview, OverviewItem{ID: l.ID, Name: l.Name, Count: len(l.UsedBy)})
+		if i, ok := byID[l.ID]; ok {
+			overview[i].Count += len(l.UsedBy)
+
+			continue
+		}
+
+		anchor := l.Anchor
+		if anchor == "" {
+			anchor = l.ID
+		}
+
+		byID[l.ID] = len(overview)
+		overview = append(overview, OverviewItem{ID: l.ID, Name: l.Name, Anchor: anchor, Count: len(l.UsedBy)})
 	}
 
 	sort.Slice(overview, func(i, j int) bool {
@@ -176,20 +245,30 @@ func buildOverview(licenses []LicenseBlock) []OverviewItem {
 
 func buildNotices(byKey map[string]OutComponent) []OutComponent {
 	notices := make([]OutComponent, 0, len(byKey))
-	for _, c := range byKey {
-		if strings.TrimSpace(c.Copyright) != "" {
+	for _, c := range buildComponents(byKey) {
+		if strings.TrimSpace(c.Copyright) != "" || len(c.NoticeTexts) > 0 || len(c.Notes) > 0 || len(c.Modifications) > 0 {
 			notices = append(notices, c)
 		}
 	}
 
-	sort.Slice(notices, func(i, j int) bool {
-		return sortComponents(notices[i], notices[j])
+	return notices
+}
+
+// buildComponents returns all the components, sorted.
+func buildComponents(byKey map[string]OutComponent) []OutComponent {
+	components := make([]OutComponent, 0, len(byKey))
+	for _, c := range byKey {
+		components = append(components, c)
+	}
+
+	sort.Slice(components, func(i, j int) bool {
+		return sortComponents(components[i], components[j])
 	})
 
-	return notices
+	return components
 }
 
-func buildLicenseBlocks(ctx context.Context, cfg Config, byLicense map[string][]OutComponent, spdxNames map[string]string) ([]LicenseBlock, error) {
+func buildLicenseBlocks(ctx context.Context, cfg Config, byLicense map[string][]OutComponent, spdxNames map[string]string, textOf componentTextFunc) ([]LicenseBlock, error) {
 	licenseIDs := make([]string, 0, len(byLicense))
 	for id := range byLicense {
 		licenseIDs = append(licenseIDs, id)
@@ -215,22 +294,51 @@ func buildLicenseBlocks(ctx context.Context, cfg Config, byLicense map[string][]
 			}
 		}
 
-		var text string
+		variants := groupLicenseVariants(id, comps, textOf)
 
-		t, errl := getLicenseText(ctx, cfg, id)
-		if errl != nil {
-			unknowns = append(unknowns, id)
-			text = fmt.Sprintf("ERROR: Could not retrieve license text for %s: %v", id, errl)
-		} else {
-			text = t
+		// Without components using the SPDX text, the ID is still validated, so
+		// that an unknown license is reported whatever the license texts used.
+		if variants[0].Hash != "" && !strings.HasPrefix(id, "LicenseRef-") {
+			if _, errl := getLicenseText(ctx, cfg, id); errl != nil {
+				unknowns = append(unknowns, id)
+			}
 		}
 
-		licenses = append(licenses, LicenseBlock{
-			ID:     id,
-			Name:   name,
-			Text:   text,
-			UsedBy: comps,
-		})
+		for _, variant := range variants {
+			block := LicenseBlock{
+				ID:       id,
+				Name:     name,
+				Anchor:   id,
+				TextHash: variant.Hash,
+				Text:     variant.Text,
+				URL:      firstLicenseURL(variant.UsedBy, id),
+				UsedBy:   variant.UsedBy,
+			}
+
+			if variant.Hash != "" {
+				block.Anchor = id + "-" + variant.Hash
+				licenses = append(licenses, block)
+
+				continue
+			}
+
+			t, errl := getLicenseText(ctx, cfg, id)
+			if errl != nil && strings.HasPrefix(id, "LicenseRef-") {
+				// Custom licenses may embed their text in the SBOM.
+				if embedded := firstLicenseText(variant.UsedBy, id); embedded != "" {
+					t, errl = embedded, nil
+				}
+			}
+
+			if errl != nil {
+				unknowns = append(unknowns, id)
+				block.Text = fmt.Sprintf("ERROR: Could not retrieve license text for %s: %v", id, errl)
+			} else {
+				block.Text = t
+			}
+
+			licenses = append(licenses, block)
+		}
 	}
 
 	if len(unknowns) > 0 {
@@ -243,34 +351,116 @@ func buildLicenseBlocks(ctx context.Context, cfg Config, byLicense map[string][]
 	return licenses, nil
 }
 
-func buildIndex(components []Component, filters Filters, licenseMap, licenseCorrections map[string]string, enricher copyrightEnricher) (map[string][]OutComponent, map[string]OutComponent) {
+func buildIndex(components []Component, in inputs, enricher copyrightEnricher, detector *licenseDetector) (map[string][]OutComponent, map[string]OutComponent) {
 	byLicense := map[string][]OutComponent{}
 	byKey := map[string]OutComponent{}
+	excluded := filterReport{}
 
 	for _, c := range components {
-		if shouldIgnoreComponent(c, filters) {
-			continue
+		ids := normalizeLicenseIDs(c.Licenses, in.licenseMap)
+		licenseSource := licenseSourceSBOM
+		// choices are the licenses ids were resolved from, for the expression.
+		choices := c.Licenses
+
+		// Fall back to the licenses the SBOM generator found in the component
+		// files.
+		if len(ids) == 0 && c.Evidence != nil {
+			ids = normalizeLicenseIDs(c.Evidence.Licenses, in.licenseMap)
+			licenseSource = licenseSourceEvidence
+			choices = c.Evidence.Licenses
 		}
 
-		ids := normalizeLicenseIDs(c.Licenses, licenseMap)
+		// Fall back to the licenses declared in the package metadata (e.g.
+		// Cargo.toml, pom.xml) when the SBOM reports none.
+		if len(ids) == 0 {
+			choices = nil
+
+			for _, declared := range enricher.declaredLicenses(c.PURL) {
+				// A license given by its URL only, such as a pom.xml <license>
+				// without <name>, is used when the license map knows the URL.
+				if isLicenseURL(declared) && in.licenseMap[declared] == "" {
+					log.Warn().
+						Str("component", c.Name+"@"+c.Version).
+						Str("url", declared).
+						Msg("Declared license URL missing from the license map ignored.")
+
+					continue
+				}
+
+				ids = append(ids, resolveExpression(LicenseChoice{Expression: declared}, in.licenseMap)...)
+				licenseSource = licenseSourceMetadata
+				choices = append(choices, LicenseChoice{Expression: declared})
+			}
+
+			ids = uniqSorted(ids)
+		}
 
 		// Apply license-corrections.json: entries take priority over whatever the SBOM
 		// reported, so they can both fill in absent licenses and correct wrong ones.
-		if c.PURL != "" {
-			if id := matchLicenseOverride(c.PURL, licenseCorrections); id != "" {
-				ids = []string{id}
+		correction := matchLicenseCorrection(c.PURL, in.licenseCorrections)
+		if correction != nil {
+			ids = []string{correction.License}
+			licenseSource = licenseSourceCorrection
+			choices = []LicenseChoice{{Expression: correction.License}}
+
+			log.Info().
+				Str("component", c.Name+"@"+c.Version).
+				Str("correction", correction.PURL).
+				Str("license", correction.License).
+				Str("justification", correction.Justification).
+				Msg("License corrected.")
+		}
+
+		// Filters see the licenses known before detection, which only runs for
+		// the components that are kept.
+		if rule := excludedBy(c, ids, in.filters); rule != "" {
+			excluded.add(rule, c)
+
+			continue
+		}
+
+		if detector != nil {
+			ids, licenseSource = applyDetectedLicenses(c, ids, licenseSource, detector.detect(enricher.licenseFiles(c.PURL)))
+			if licenseSource == licenseSourceDetected {
+				choices = nil
 			}
 		}
 
+		n := sbomEvidenceNotice(c)
+		if c.Copyright != "" || n.Text == "" {
+			n = enricher.enrich(c.PURL, c.Copyright)
+		}
+
+		if n.Text == "" {
+			n = sbomPartyNotice(c)
+		}
+
+		var sbomLicenses []LicenseChoice
+		if c.Evidence != nil {
+			sbomLicenses = c.Evidence.Licenses
+		}
+
+		licenseTexts, licenseURLs := embeddedLicenses(slices.Concat(c.Licenses, sbomLicenses), in.licenseMap)
+
 		out := OutComponent{
-			Name:       c.Name,
-			Version:    c.Version,
-			PURL:       c.PURL,
-			URL:        componentURLFromPurl(c.PURL),
-			LicenseIDs: ids,
-			Copyright:  enricher.enrich(c.PURL, c.Copyright),
+			Name:              c.Name,
+			Version:           c.Version,
+			PURL:              c.PURL,
+			URL:               componentURL(c),
+			DocumentationURL:  componentDocumentationURL(c),
+			LicenseIDs:        ids,
+			LicenseExpression: licenseExpression(choices, ids, in.licenseMap),
+			LicenseSource:     licenseSource,
+			Correction:        correction,
+			Copyright:         n.Text,
+			CopyrightSource:   n.Source,
+			LicenseTexts:      licenseTexts,
+			LicenseURLs:       licenseURLs,
+			NoticeTexts:       noticeTexts(enricher.noticeFiles(c.PURL)),
 		}
 
+		out = applyCuration(out, in.curations)
+
 		out = mergeOrInsert(byKey, c, out)
 
 		for _, id := range ids {
@@ -278,9 +468,108 @@ func buildIndex(components []Component, filters Filters, licenseMap, licenseCorr
 		}
 	}
 
+	excluded.log()
+
 	return byLicense, byKey
 }
 
+// joinLicenseExpressions returns the conjunction of the license expressions a
+// and b of the same component, e.g. "(MIT OR Apache-2.0) AND BSD-3-Clause". b
+// is left out when a already requires it.
+func joinLicenseExpressions(a, b string) string {
+	group := func(expr string) string {
+		if strings.Contains(expr, " ") {
+			return "(" + expr + ")"
+		}
+
+		return expr
+	}
+
+	switch {
+	case a == "":
+		return b
+	case b == "" || a == b || slices.Contains(conjuncts(a), group(b)):
+		return a
+	}
+
+	return group(a) + " AND " + group(b)
+}
+
+// conjuncts returns the operands of the top-level AND operators of expr.
+func conjuncts(expr string) []string {
+	var (
+		parts []string
+		depth int
+		start int
+	)
+
+	for i := 0; i < len(expr); i++ {
+		switch {
+		case expr[i] == '(':
+			depth++
+		case expr[i] == ')':
+			depth--
+		case depth == 0 && strings.HasPrefix(expr[i:], " AND "):
+			parts = append(parts, expr[start:i])
+			start = i + len(" AND ")
+		}
+	}
+
+	return append(parts, expr[start:])
+}
+
+// isLicenseURL reports whether a declared license is a URL rather than a name.
+func isLicenseURL(license string) bool {
+	return strings.HasPrefix(license, "http://") || strings.HasPrefix(license, "https://")
+}
+
+// noticeTexts returns the non-blank texts of the NOTICE files.
+func noticeTexts(files []licenseFile) []string {
+	var texts []string
+
+	for _, f := range files {
+		if text := strings.TrimSpace(f.Text); text != "" {
+			texts = append(texts, text)
+		}
+	}
+
+	return texts
+}
+
+// applyDetectedLicenses fills in the licenses of a component that has none with
+// the ones detected in its license files, and warns when the detected licenses
+// disagree with the resolved ones.
+func applyDetectedLicenses(c Component, ids []string, source string, detected []string) ([]string, string) {
+	if len(detected) == 0 {
+		return ids, source
+	}
+
+	if len(ids) == 0 {
+		log.Info().
+			Str("component", c.Name+"@"+c.Version).
+			Strs("detected", detected).
+			Msg("License detected from the package license files.")
+
+		return detected, licenseSourceDetected
+	}
+
+	for _, id := range detected {
+		if !slices.Contains(ids, id) {
+			log.Warn().
+				Str("component", c.Name+"@"+c.Version).
+				Str("purl", c.PURL).
+				Str("license_source", source).
+				Strs("declared", ids).
+				Strs("detected", detected).
+				Msg("Detected license differs from the declared one.")
+
+			break
+		}
+	}
+
+	return ids, source
+}
+
 // sortComponents orders components by name, version, PURL, URL, copyright in
 // turn. Concatenating these into one string would conflate boundaries — e.g.
 // ("ab", "") and ("a", "b") would compare equal — so the comparison cascades
@@ -305,16 +594,44 @@ func sortComponents(a, b OutComponent) bool {
 	return a.Copyright < b.Copyright
 }
 
-func mergeOrInsert(byKey map[string]OutComponent, c Component, out OutComponent) OutComponent {
-	key := c.PURL
-	if key == "" {
-		key = c.Name + "@" + c.Version
+// componentKey identifies a component: its PURL, or its name and version.
+func componentKey(c Component) string {
+	if c.PURL != "" {
+		return c.PURL
 	}
 
+	return c.Name + "@" + c.Version
+}
+
+func mergeOrInsert(byKey map[string]OutComponent, c Component, out OutComponent) OutComponent {
+	key := componentKey(c)
+
 	if existing, ok := byKey[key]; ok {
 		existing.LicenseIDs = uniqSorted(append(existing.LicenseIDs, out.LicenseIDs...))
+		existing.LicenseExpression = joinLicenseExpressions(existing.LicenseExpression, out.LicenseExpression)
+
 		if existing.Copyright == "" && out.Copyright != "" {
 			existing.Copyright = out.Copyright
+			existing.CopyrightSource = out.CopyrightSource
+		}
+
+		if existing.URL == "" {
+			existing.URL = out.URL
+		}
+
+		if existing.DocumentationURL == "" {
+			existing.DocumentationURL = out.DocumentationURL
+		}
+
+		if existing.Correction == nil {
+			existing.Correction = out.Correction
+		}
+
+		existing.LicenseTexts = mergeMissing(existing.LicenseTexts, out.LicenseTexts)
+		existing.LicenseURLs = mergeMissing(existing.LicenseURLs, out.LicenseURLs)
+
+		if len(existing.NoticeTexts) == 0 {
+			existing.NoticeTexts = out.NoticeTexts
 		}
 
 		byKey[key] = existing
@@ -1,6 +1,9 @@
 package generator
 
 import (
+	"context"
+	"os"
+	"path/filepath"
 	"regexp"
 	"testing"
 
@@ -52,23 +55,61 @@ func TestBuildNotices_FilterEmptyCopyright(t *testing.T) {
 	require.Equal(t, "foo", out[1].Name)
 }
 
+func TestBuildComponents_IncludeEmptyCopyright(t *testing.T) {
+	t.Parallel()
+
+	in := map[string]OutComponent{
+		"1": {Name: "foo", Version: "1", Copyright: "c foo"},
+		"2": {Name: "bar", Version: "1"},
+	}
+
+	out := buildComponents(in)
+
+	require.Len(t, out, 2)
+	require.Equal(t, "bar", out[0].Name)
+	require.Equal(t, "foo", out[1].Name)
+}
+
+func TestBuildIndex_LicenseExpression(t *testing.T) {
+	t.Parallel()
+
+	components := []Component{
+		{Name: "foo", Version: "1.0.0", PURL: "pkg:npm/foo@1.0.0", Licenses: []LicenseChoice{
+			{Expression: "(MIT OR Apache 2.0) AND BSD-3-Clause"},
+		}},
+		{Name: "bar", Version: "1.0.0", PURL: "pkg:npm/bar@1.0.0", Licenses: []LicenseChoice{
+			{License: &License{ID: "MIT"}},
+			{License: &License{ID: "ISC"}},
+		}},
+		{Name: "baz", Version: "1.0.0", PURL: "pkg:npm/baz@1.0.0", Licenses: []LicenseChoice{
+			{Expression: "GPL-2.0"},
+		}},
+	}
+	corrections := map[string]LicenseCorrections{
+		"pkg:npm/baz": correctionTo("MIT"),
+	}
+
+	_, byKey := buildIndex(components, inputs{licenseCorrections: corrections}, copyrightEnricher{}, nil)
+
+	require.Equal(t, "(MIT OR Apache-2.0) AND BSD-3-Clause", byKey["pkg:npm/foo@1.0.0"].LicenseExpression)
+	require.Equal(t, "ISC AND MIT", byKey["pkg:npm/bar@1.0.0"].LicenseExpression)
+	require.Equal(t, "MIT", byKey["pkg:npm/baz@1.0.0"].LicenseExpression)
+}
+
 func TestBuildIndex_LicenseOverrideForComponentWithoutLicense(t *testing.T) {
 	t.Parallel()
 
 	components := []Component{
 		{Name: "std", Version: "go1.25.3", PURL: "pkg:golang/std@go1.25.3"},
 		{Name: "foo", Version: "1.0.0", PURL: "pkg:npm/foo@1.0.0", Licenses: []LicenseChoice{
-			{License: &struct {
-				ID   string `json:"id"`
-				Name string `json:"name"`
-			}{ID: "MIT"}},
+			{License: &License{ID: "MIT"}},
 		}},
 	}
-	overrides := map[string]string{
-		"pkg:golang/std": "BSD-3-Clause",
+	overrides := map[string]LicenseCorrections{
+		"pkg:golang/std": correctionTo("BSD-3-Clause"),
 	}
 
-	byLicense, byKey := buildIndex(components, Filters{}, nil, overrides, copyrightEnricher{})
+	byLicense, byKey := buildIndex(components, inputs{licenseCorrections: overrides}, copyrightEnricher{}, nil)
 
 	require.Contains(t, byLicense, "BSD-3-Clause")
 	require.Contains(t, byLicense, "MIT")
@@ -80,20 +121,19 @@ func TestBuildIndex_OverrideReplacesExistingLicense(t *testing.T) {
 
 	components := []Component{
 		{Name: "foo", Version: "1.0.0", PURL: "pkg:npm/foo@1.0.0", Licenses: []LicenseChoice{
-			{License: &struct {
-				ID   string `json:"id"`
-				Name string `json:"name"`
-			}{ID: "Apache-2.0"}},
+			{License: &License{ID: "Apache-2.0"}},
 		}},
 	}
-	overrides := map[string]string{
-		"pkg:npm/foo": "MIT",
+	overrides := map[string]LicenseCorrections{
+		"pkg:npm/foo": correctionTo("MIT"),
 	}
 
-	_, byKey := buildIndex(components, Filters{}, nil, overrides, copyrightEnricher{})
+	_, byKey := buildIndex(components, inputs{licenseCorrections: overrides}, copyrightEnricher{}, nil)
 
 	// missing-licenses entries take priority and correct wrong licenses from the SBOM.
 	require.Equal(t, []string{"MIT"}, byKey["pkg:npm/foo@1.0.0"].LicenseIDs)
+	require.NotNil(t, byKey["pkg:npm/foo@1.0.0"].Correction)
+	require.Equal(t, "pkg:npm/foo", byKey["pkg:npm/foo@1.0.0"].Correction.PURL)
 }
 
 func TestBuildIndex_MergesDuplicateComponents(t *testing.T) {
@@ -101,55 +141,78 @@ func TestBuildIndex_MergesDuplicateComponents(t *testing.T) {
 
 	components := []Component{
 		{Name: "foo", Version: "1.0.0", PURL: "pkg:npm/foo@1.0.0", Licenses: []LicenseChoice{
-			{License: &struct {
-				ID   string `json:"id"`
-				Name string `json:"name"`
-			}{ID: "MIT"}},
+			{License: &License{ID: "MIT"}},
 		}},
 		{Name: "foo", Version: "1.0.0", PURL: "pkg:npm/foo@1.0.0", Copyright: "(c) Foo Inc", Licenses: []LicenseChoice{
-			{License: &struct {
-				ID   string `json:"id"`
-				Name string `json:"name"`
-			}{ID: "Apache-2.0"}},
+			{License: &License{ID: "Apache-2.0"}},
 		}},
 	}
 
-	_, byKey := buildIndex(components, Filters{}, nil, nil, copyrightEnricher{})
+	_, byKey := buildIndex(components, inputs{}, copyrightEnricher{}, nil)
 
 	merged := byKey["pkg:npm/foo@1.0.0"]
 	require.Equal(t, []string{"Apache-2.0", "MIT"}, merged.LicenseIDs)
+	require.Equal(t, "MIT AND Apache-2.0", merged.LicenseExpression)
 	require.Equal(t, "(c) Foo Inc", merged.Copyright)
+	require.Equal(t, "sbom", merged.CopyrightSource)
 }
 
-func TestShouldIgnoreComponent(t *testing.T) {
+func TestBuildIndex_MergesDuplicateLicenseExpressions(t *testing.T) {
 	t.Parallel()
 
-	filters := Filters{
+	components := []Component{
+		{Name: "foo", Version: "1.0.0", PURL: "pkg:npm/foo@1.0.0", Licenses: []LicenseChoice{
+			{Expression: "MIT OR Apache-2.0"},
+		}},
+		{Name: "foo", Version: "1.0.0", PURL: "pkg:npm/foo@1.0.0", Licenses: []LicenseChoice{
+			{Expression: "BSD-3-Clause"},
+		}},
+		{Name: "foo", Version: "1.0.0", PURL: "pkg:npm/foo@1.0.0", Licenses: []LicenseChoice{
+			{Expression: "BSD-3-Clause"},
+		}},
+	}
+
+	_, byKey := buildIndex(components, inputs{}, copyrightEnricher{}, nil)
+
+	merged := byKey["pkg:npm/foo@1.0.0"]
+	require.Equal(t, []string{"Apache-2.0", "BSD-3-Clause", "MIT"}, merged.LicenseIDs)
+	require.Equal(t, "(MIT OR Apache-2.0) AND BSD-3-Clause", merged.LicenseExpression)
+}
+
+func TestJoinLicenseExpressions(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, "MIT", joinLicenseExpressions("MIT", "MIT"))
+	require.Equal(t, "MIT", joinLicenseExpressions("", "MIT"))
+	require.Equal(t, "MIT OR ISC", joinLicenseExpressions("MIT OR ISC", ""))
+	require.Equal(t, "(MIT OR ISC) AND (Apache-2.0 AND BSD-3-Clause)", joinLicenseExpressions("MIT OR ISC", "Apache-2.0 AND BSD-3-Clause"))
+	require.Equal(t, "(MIT OR ISC) AND BSD-3-Clause", joinLicenseExpressions("(MIT OR ISC) AND BSD-3-Clause", "BSD-3-Clause"))
+	require.Equal(t, "(MIT AND ISC OR 0BSD) AND ISC", joinLicenseExpressions("MIT AND ISC OR 0BSD", "ISC"))
+}
+
+func TestExcludedBy_PURLAndSupplier(t *testing.T) {
+	t.Parallel()
+
+	filters := Filters{FilterRules: FilterRules{
 		PURLRegex: []*regexp.Regexp{
 			regexp.MustCompile(`use\.local`),
 		},
 		Suppliers: []*regexp.Regexp{
 			regexp.MustCompile("^Foo$"),
 		},
-	}
+	}}
 
 	c1 := Component{
-		PURL: "pkg:golang/github.com/some/repo",
-		Supplier: &struct {
-			Name string `json:"name"`
-		}{Name: "Some Supplier"},
+		PURL:     "pkg:golang/github.com/some/repo",
+		Supplier: &OrganizationalEntity{Name: "Some Supplier"},
 	}
 	c2 := Component{
-		PURL: "pkg:npm/foo@1.2.30",
-		Supplier: &struct {
-			Name string `json:"name"`
-		}{Name: "Foo"},
+		PURL:     "pkg:npm/foo@1.2.30",
+		Supplier: &OrganizationalEntity{Name: "Foo"},
 	}
 	c3 := Component{
-		PURL: "pkg:golang/use.local/bar@v1.0.0",
-		Supplier: &struct {
-			Name string `json:"name"`
-		}{Name: ""},
+		PURL:     "pkg:golang/use.local/bar@v1.0.0",
+		Supplier: &OrganizationalEntity{Name: ""},
 	}
 
 	// c4 has nil supplier (e.g., cyclonedx-gomod output).
@@ -158,8 +221,74 @@ func TestShouldIgnoreComponent(t *testing.T) {
 		Supplier: nil,
 	}
 
-	require.False(t, shouldIgnoreComponent(c1, filters))
-	require.True(t, shouldIgnoreComponent(c2, filters))
-	require.True(t, shouldIgnoreComponent(c3, filters))
-	require.False(t, shouldIgnoreComponent(c4, filters))
+	require.Empty(t, excludedBy(c1, nil, filters))
+	require.Equal(t, `supplier "^Foo$"`, excludedBy(c2, nil, filters))
+	require.Equal(t, "purlRegex", excludedBy(c3, nil, filters))
+	require.Empty(t, excludedBy(c4, nil, filters))
+}
+
+func TestBuildIndex_Evidence(t *testing.T) {
+	t.Parallel()
+
+	components := []Component{{
+		Name:      "foo",
+		Version:   "1.0.0",
+		Copyright: "",
+		Evidence: &Evidence{
+			Licenses: []LicenseChoice{{License: &License{ID: "MIT"}}},
+			Copyright: []struct {
+				Text string `json:"text"`
+			}{{Text: "Copyright (c) Foo Inc"}, {Text: "Copyright (c) Bar Ltd"}, {Text: "Copyright (c) Foo Inc"}},
+		},
+	}}
+
+	_, byKey := buildIndex(components, inputs{}, copyrightEnricher{}, nil)
+
+	got := byKey["foo@1.0.0"]
+	require.Equal(t, []string{"MIT"}, got.LicenseIDs)
+	require.Equal(t, licenseSourceEvidence, got.LicenseSource)
+	require.Equal(t, "Copyright (c) Foo Inc\nCopyright (c) Bar Ltd", got.Copyright)
+	require.Equal(t, "sbom evidence", got.CopyrightSource)
+
+	// Licenses and copyright of the component itself take priority.
+	components[0].Licenses = []LicenseChoice{{Expression: "Apache-2.0"}}
+	components[0].Copyright = "Copyright (c) Baz"
+
+	_, byKey = buildIndex(components, inputs{}, copyrightEnricher{}, nil)
+
+	got = byKey["foo@1.0.0"]
+	require.Equal(t, []string{"Apache-2.0"}, got.LicenseIDs)
+	require.Equal(t, licenseSourceSBOM, got.LicenseSource)
+	require.Equal(t, "sbom", got.CopyrightSource)
+}
+
+func TestBuildLicenseBlocks_EmbeddedLicenseRefText(t *testing.T) {
+	t.Parallel()
+
+	components := []Component{{
+		Name:    "foo",
+		Version: "1.0.0",
+		Licenses: []LicenseChoice{{License: &License{
+			ID:   "LicenseRef-Acme",
+			Text: &AttachedText{Content: "Acme license text"},
+			URL:  "https://acme.example.com/license",
+		}}},
+	}}
+
+	byLicense, _ := buildIndex(components, inputs{}, copyrightEnricher{}, nil)
+
+	blocks, err := buildLicenseBlocks(context.Background(), Config{OutLicensesDir: t.TempDir()}, byLicense, nil, nil)
+	require.NoError(t, err)
+	require.Len(t, blocks, 1)
+	require.Equal(t, "Acme license text", blocks[0].Text)
+	require.Equal(t, "https://acme.example.com/license", blocks[0].URL)
+
+	// A custom license file still takes priority over the embedded text.
+	dir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "custom"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "custom", "LicenseRef-Acme.txt"), []byte("curated text"), 0o644))
+
+	blocks, err = buildLicenseBlocks(context.Background(), Config{OutLicensesDir: dir}, byLicense, nil, nil)
+	require.NoError(t, err)
+	require.Equal(t, "curated text", blocks[0].Text)
 }
@@ -0,0 +1,234 @@
+package generator
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+)
+
+// embeddedLayer is the name of the layer of the data files shipped with
+// assimilis.
+const embeddedLayer = "embedded"
+
+// dataLayer is one layer of a data file. Layers are merged from the embedded
+// defaults to the organization-wide data, then the repository data.
+type dataLayer[T any] struct {
+	name string
+	data T
+}
+
+// readDataLayers reads the layers of a data file: the embedded file, the file
+// of the same name in orgDir, then the repository file at repoPath. The
+// organization file is optional.
+func readDataLayers[T any](embeddedPath, orgDir, repoPath string) ([]dataLayer[T], error) {
+	data, err := readJSON[T](embedded.ReadFile, embeddedPath)
+	if err != nil {
+		return nil, err
+	}
+
+	layers := []dataLayer[T]{{name: embeddedLayer, data: data}}
+
+	if orgDir != "" {
+		if !isDir(orgDir) {
+			return nil, fmt.Errorf("organization data directory %q not found", orgDir)
+		}
+
+		orgPath := filepath.Join(orgDir, path.Base(embeddedPath))
+
+		data, err := readJSON[T](os.ReadFile, orgPath)
+		switch {
+		case err == nil:
+			layers = append(layers, dataLayer[T]{name: orgPath, data: data})
+		case !errors.Is(err, fs.ErrNotExist):
+			return nil, err
+		}
+	}
+
+	if repoPath != "" {
+		data, err := readJSON[T](os.ReadFile, repoPath)
+		if err != nil {
+			return nil, err
+		}
+
+		layers = append(layers, dataLayer[T]{name: repoPath, data: data})
+	}
+
+	return layers, nil
+}
+
+// loadDataMap reads and merges the layers of a license map or corrections
+// file. inline, when not nil, is the repository layer given in the
+// configuration file. removes reports whether an entry removes the inherited
+// one.
+func loadDataMap[V any](kind, embeddedPath, orgDir, repoPath string, inline map[string]V, removes func(V) bool) (map[string]V, error) {
+	layers, err := readDataLayers[map[string]V](embeddedPath, orgDir, repoPath)
+	if err != nil {
+		return nil, err
+	}
+
+	if inline != nil {
+		layers = append(layers, dataLayer[map[string]V]{name: "config file", data: inline})
+	}
+
+	return mergeDataMaps(kind, layers, removes), nil
+}
+
+// mergeDataMaps merges the layers of a map: entries of a layer override the
+// ones of the previous layers, and an entry for which removes is true (such as
+// a null value) removes the inherited one. Entries that do not come from the
+// embedded layer are logged along with their layer.
+func mergeDataMaps[V any](kind string, layers []dataLayer[map[string]V], removes func(V) bool) map[string]V {
+	merged := map[string]V{}
+	origins := map[string]string{}
+
+	for _, layer := range layers {
+		keys := make([]string, 0, len(layer.data))
+		for key := range layer.data {
+			keys = append(keys, key)
+		}
+
+		sort.Strings(keys)
+
+		for _, key := range keys {
+			value := layer.data[key]
+			inherited, exists := origins[key]
+
+			if removes(value) {
+				if !exists {
+					log.Warn().
+						Str("data", kind).
+						Str("key", key).
+						Str("layer", layer.name).
+						Msg("No inherited entry to remove.")
+
+					continue
+				}
+
+				delete(merged, key)
+				delete(origins, key)
+
+				log.Info().
+					Str("data", kind).
+					Str("key", key).
+					Str("layer", layer.name).
+					Str("inherited_from", inherited).
+					Msg("Data entry removed.")
+
+				continue
+			}
+
+			merged[key] = value
+			origins[key] = layer.name
+
+			if layer.name == embeddedLayer {
+				continue
+			}
+
+			event := log.Info().
+				Str("data", kind).
+				Str("key", key).
+				Interface("value", value).
+				Str("layer", layer.name)
+			if exists {
+				event.Str("overrides", inherited).Msg("Data entry overridden.")
+			} else {
+				event.Msg("Data entry added.")
+			}
+		}
+	}
+
+	return merged
+}
+
+// isBlank reports whether a license map entry is empty, which removes the
+// inherited entry.
+func isBlank(value string) bool {
+	return strings.TrimSpace(value) == ""
+}
+
+// hasNoCorrection reports whether a license corrections entry is null, which
+// removes the inherited entry.
+func hasNoCorrection(corrections LicenseCorrections) bool {
+	return len(corrections) == 0
+}
+
+// loadFilters reads and merges the layers of the filters.
+func loadFilters(cfg Config) (Filters, error) {
+	layers, err := readDataLayers[Filters](embeddedFiltersPath, cfg.OrgDataDir, cfg.FiltersPath)
+	if err != nil {
+		return Filters{}, err
+	}
+
+	if cfg.Filters != nil {
+		layers = append(layers, dataLayer[Filters]{name: "config file", data: *cfg.Filters})
+	}
+
+	return mergeFilters(layers), nil
+}
+
+// mergeFilters merges the layers of the filters: the rules of each layer are
+// added to the ones of the previous layers. A rule prefixed with "!" removes
+// the inherited rule, e.g. "!use\\.local" in purlRegex, or "!excluded" in
+// scopes. Property rules are identified by their name.
+func mergeFilters(layers []dataLayer[Filters]) Filters {
+	var merged Filters
+
+	for _, layer := range layers {
+		merged.FilterRules = mergeFilterRules(merged.FilterRules, layer.data.FilterRules, layer.name, "")
+		merged.Include = mergeFilterRules(merged.Include, layer.data.Include, layer.name, "include.")
+	}
+
+	return merged
+}
+
+func mergeFilterRules(dst, src FilterRules, layer, prefix string) FilterRules {
+	dst.PURLRegex = mergeFilterList(dst.PURLRegex, src.PURLRegex, (*regexp.Regexp).String, layer, prefix+"purlRegex")
+	dst.Suppliers = mergeFilterList(dst.Suppliers, src.Suppliers, (*regexp.Regexp).String, layer, prefix+"suppliers")
+	dst.Scopes = mergeFilterList(dst.Scopes, src.Scopes, strings.ToLower, layer, prefix+"scopes")
+	dst.Types = mergeFilterList(dst.Types, src.Types, strings.ToLower, layer, prefix+"types")
+	dst.Groups = mergeFilterList(dst.Groups, src.Groups, (*regexp.Regexp).String, layer, prefix+"groups")
+	dst.Licenses = mergeFilterList(dst.Licenses, src.Licenses, (*regexp.Regexp).String, layer, prefix+"licenses")
+	dst.Properties = mergeFilterList(dst.Properties, src.Properties, func(p PropertyFilter) string { return p.Name }, layer, prefix+"properties")
+
+	return dst
+}
+
+func mergeFilterList[T any](dst, src []T, key func(T) string, layer, field string) []T {
+	for _, entry := range src {
+		k := key(entry)
+
+		if removed, ok := strings.CutPrefix(k, "!"); ok {
+			n := len(dst)
+			dst = slices.DeleteFunc(dst, func(e T) bool { return key(e) == removed })
+
+			if n == len(dst) {
+				log.Warn().Str("filter", field).Str("entry", removed).Str("layer", layer).Msg("No inherited entry to remove.")
+			} else {
+				log.Info().Str("filter", field).Str("entry", removed).Str("layer", layer).Msg("Filter entry removed.")
+			}
+
+			continue
+		}
+
+		if slices.ContainsFunc(dst, func(e T) bool { return key(e) == k }) {
+			continue
+		}
+
+		dst = append(dst, entry)
+
+		if layer != embeddedLayer {
+			log.Info().Str("filter", field).Str("entry", k).Str("layer", layer).Msg("Filter entry added.")
+		}
+	}
+
+	return dst
+}
@@ -0,0 +1,149 @@
+package generator
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func TestMergeDataMaps(t *testing.T) {
+	t.Parallel()
+
+	var org, repo map[string]string
+	require.NoError(t, json.Unmarshal([]byte(`{"Apache 2": "Apache-2.0", "BSD": "BSD-2-Clause"}`), &org))
+	require.NoError(t, json.Unmarshal([]byte(`{"BSD": "BSD-3-Clause", "GPL": null, "Unknown": null}`), &repo))
+
+	layers := []dataLayer[map[string]string]{
+		{name: embeddedLayer, data: map[string]string{"GPL": "GPL-2.0-only", "MIT License": "MIT"}},
+		{name: "org/license-map.json", data: org},
+		{name: "license-map.json", data: repo},
+	}
+
+	merged := mergeDataMaps("license map", layers, isBlank)
+
+	assert.Equal(t, map[string]string{
+		"Apache 2":    "Apache-2.0",
+		"BSD":         "BSD-3-Clause",
+		"MIT License": "MIT",
+	}, merged)
+}
+
+func TestMergeDataMaps_YAMLNull(t *testing.T) {
+	t.Parallel()
+
+	var inline map[string]string
+	require.NoError(t, yaml.Unmarshal([]byte("MIT License: ~\n"), &inline))
+
+	merged := mergeDataMaps("license map", []dataLayer[map[string]string]{
+		{name: embeddedLayer, data: map[string]string{"MIT License": "MIT"}},
+		{name: "config file", data: inline},
+	}, isBlank)
+
+	assert.Empty(t, merged)
+}
+
+func TestMergeFilters(t *testing.T) {
+	t.Parallel()
+
+	var org, repo Filters
+	require.NoError(t, json.Unmarshal([]byte(`{
+		"purlRegex": ["^pkg:golang/example\\.com/", "!use\\.local"],
+		"scopes": ["optional"],
+		"include": {"purlRegex": ["^pkg:npm/typescript@"]}
+	}`), &org))
+	require.NoError(t, json.Unmarshal([]byte(`{
+		"suppliers": ["!Traefik Labs"],
+		"scopes": ["!Optional", "excluded"],
+		"properties": [{"name": "!cdx:npm:package:development"}]
+	}`), &repo))
+
+	embeddedFilters := Filters{FilterRules: FilterRules{
+		PURLRegex:  []*regexp.Regexp{regexp.MustCompile(`use\.local`)},
+		Suppliers:  []*regexp.Regexp{regexp.MustCompile(`Traefik Labs`)},
+		Scopes:     []string{"excluded"},
+		Properties: []PropertyFilter{{Name: "cdx:npm:package:development", Value: regexp.MustCompile(`^true$`)}},
+	}}
+
+	merged := mergeFilters([]dataLayer[Filters]{
+		{name: embeddedLayer, data: embeddedFilters},
+		{name: "org/filters.json", data: org},
+		{name: "filters.json", data: repo},
+	})
+
+	require.Len(t, merged.PURLRegex, 1)
+	assert.Equal(t, `^pkg:golang/example\.com/`, merged.PURLRegex[0].String())
+	assert.Empty(t, merged.Suppliers)
+	assert.Equal(t, []string{"excluded"}, merged.Scopes)
+	assert.Empty(t, merged.Properties)
+	require.Len(t, merged.Include.PURLRegex, 1)
+
+	// The embedded layer is left untouched.
+	assert.Len(t, embeddedFilters.PURLRegex, 1)
+	assert.Len(t, embeddedFilters.Properties, 1)
+}
+
+func TestReadDataLayers(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+
+	orgDir := filepath.Join(dir, "org")
+	require.NoError(t, os.MkdirAll(orgDir, 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(orgDir, "license-map.json"), []byte(`{"Org License": "MIT"}`), 0o644))
+
+	repoPath := filepath.Join(dir, "license-map.json")
+	require.NoError(t, os.WriteFile(repoPath, []byte(`{"Repo License": "MIT"}`), 0o644))
+
+	layers, err := readDataLayers[map[string]string](embeddedLicenseMapPath, orgDir, repoPath)
+	require.NoError(t, err)
+
+	require.Len(t, layers, 3)
+	assert.Equal(t, embeddedLayer, layers[0].name)
+	assert.NotEmpty(t, layers[0].data)
+	assert.Equal(t, filepath.Join(orgDir, "license-map.json"), layers[1].name)
+	assert.Equal(t, repoPath, layers[2].name)
+
+	// The organization files are optional.
+	corrections, err := readDataLayers[map[string]LicenseCorrections](embeddedLicenseCorrectionsPath, orgDir, "")
+	require.NoError(t, err)
+	assert.Len(t, corrections, 1)
+
+	_, err = readDataLayers[map[string]string](embeddedLicenseMapPath, filepath.Join(dir, "missing"), "")
+	require.Error(t, err)
+
+	_, err = readDataLayers[map[string]string](embeddedLicenseMapPath, "", filepath.Join(dir, "missing.json"))
+	require.Error(t, err)
+}
+
+func TestLoadDataMap_Inline(t *testing.T) {
+	t.Parallel()
+
+	merged, err := loadDataMap("license map", embeddedLicenseMapPath, "", "", map[string]string{"Custom License": "MIT"}, isBlank)
+	require.NoError(t, err)
+
+	assert.Equal(t, "MIT", merged["Custom License"])
+	assert.Greater(t, len(merged), 1)
+}
+
+func TestMergeDataMaps_LicenseCorrections(t *testing.T) {
+	t.Parallel()
+
+	var repo map[string]LicenseCorrections
+	require.NoError(t, json.Unmarshal([]byte(`{
+		"pkg:npm/foo": null,
+		"pkg:npm/bar": {"license": "ISC", "justification": "LICENSE file."}
+	}`), &repo))
+
+	merged := mergeDataMaps("license corrections", []dataLayer[map[string]LicenseCorrections]{
+		{name: embeddedLayer, data: map[string]LicenseCorrections{"pkg:npm/foo": correctionTo("MIT"), "pkg:npm/bar": correctionTo("MIT")}},
+		{name: "corrections.json", data: repo},
+	}, hasNoCorrection)
+
+	assert.Equal(t, map[string]LicenseCorrections{"pkg:npm/bar": correctionTo("ISC")}, merged)
+}
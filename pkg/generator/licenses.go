@@ -1,6 +1,7 @@
 package generator
 
 import (
+	"encoding/base64"
 	"slices"
 	"strings"
 
@@ -71,6 +72,49 @@ func resolveExpression(item LicenseChoice, licenseMap map[string]string) []strin
 	return ids
 }
 
+// licenseExpression returns the SPDX license expression of a component whose
+// licenses were resolved to ids from choices. A single expression keeps its
+// operators, with its licenses resolved like the IDs; several licenses are
+// combined with AND.
+func licenseExpression(choices []LicenseChoice, ids []string, licenseMap map[string]string) string {
+	if len(choices) == 1 && choices[0].License == nil {
+		if expr := resolveLicenseExpression(choices[0].Expression, licenseMap); expr != "" {
+			return expr
+		}
+	}
+
+	return strings.Join(ids, " AND ")
+}
+
+// resolveLicenseExpression resolves the licenses of expr as resolveExpression
+// does, and returns it as a string, or "" when it cannot be parsed.
+func resolveLicenseExpression(expr string, licenseMap map[string]string) string {
+	expr = stripTrovePrefix(strings.TrimSpace(expr))
+	if expr == "" {
+		return ""
+	}
+
+	if mapped, ok := licenseMap[expr]; ok && mapped != "" {
+		return mapped
+	}
+
+	resolve := func(e expression.Expression) expression.Expression {
+		// Exceptions, the right side of WITH, are kept as is.
+		if simple, ok := e.(expression.SimpleExpr); ok && !expression.ValidateSPDXException(simple.License) {
+			return expression.SimpleExpr{License: resolveSingleLicense(simple.String(), licenseMap)}
+		}
+
+		return e
+	}
+
+	parsed, err := expression.Normalize(expr, expression.NormalizeForSPDX, resolve)
+	if err != nil {
+		return ""
+	}
+
+	return parsed.String()
+}
+
 // collectSimpleLicenses walks the parsed license expression and returns the
 // SPDX-like string of every leaf SimpleExpr, in left-to-right order.
 func collectSimpleLicenses(e expression.Expression) []string {
@@ -113,44 +157,6 @@ func resolveSingleLicense(lic string, licenseMap map[string]string) string {
 	return licRef
 }
 
-// matchLicenseOverride checks if a PURL matches any entry in license-corrections.json.
-// Keys are PURL prefixes: "pkg:golang/std" matches "pkg:golang/std@go1.25.3", and
-// "pkg:golang/github.com/foo/bar" matches sub-packages like
-// "pkg:golang/github.com/foo/bar/v2/sub@v2.1.0".
-func matchLicenseOverride(purl string, overrides map[string]string) string {
-	if overrides == nil {
-		return ""
-	}
-
-	// Strip qualifiers (everything after "?") for cleaner matching.
-	clean := purl
-	if idx := strings.Index(clean, "?"); idx != -1 {
-		clean = clean[:idx]
-	}
-
-	// Try exact match first.
-	if id, ok := overrides[clean]; ok {
-		return id
-	}
-
-	// Strip version ("@...") before prefix matching.
-	if idx := strings.LastIndex(clean, "@"); idx != -1 {
-		clean = clean[:idx]
-	}
-
-	// Check whether any override key is a prefix of the version-stripped PURL.
-	// This handles sub-packages and Go major versions embedded in the path
-	// (e.g. key "pkg:golang/github.com/nrdcg/oci-go-sdk" matches
-	// "pkg:golang/github.com/nrdcg/oci-go-sdk/v65/common@v65.0.0").
-	for key, id := range overrides {
-		if clean == key || strings.HasPrefix(clean, key+"/") {
-			return id
-		}
-	}
-
-	return ""
-}
-
 func firstNonEmpty(a string, b func() string) string {
 	if strings.TrimSpace(a) != "" {
 		return a
@@ -191,3 +197,128 @@ func stripTrovePrefix(s string) string {
 
 	return s
 }
+
+// License acknowledgements (CycloneDX 1.6).
+const (
+	acknowledgementDeclared  = "declared"
+	acknowledgementConcluded = "concluded"
+)
+
+// acknowledgement returns whether the license was declared by the component
+// authors or concluded by analysis, or an empty string when unspecified.
+func (l LicenseChoice) acknowledgement() string {
+	if l.License != nil && l.License.Acknowledgement != "" {
+		return strings.ToLower(l.License.Acknowledgement)
+	}
+
+	return strings.ToLower(l.Acknowledgement)
+}
+
+// selectLicenses returns the licenses to use for a component. When the SBOM
+// lists both concluded and declared (or unqualified) licenses, the concluded
+// ones are used, unless preferDeclared is set.
+func selectLicenses(licenses []LicenseChoice, preferDeclared bool) []LicenseChoice {
+	var concluded, declared []LicenseChoice
+
+	for _, l := range licenses {
+		if l.acknowledgement() == acknowledgementConcluded {
+			concluded = append(concluded, l)
+		} else {
+			declared = append(declared, l)
+		}
+	}
+
+	if len(concluded) == 0 || len(declared) == 0 {
+		return licenses
+	}
+
+	if preferDeclared {
+		return declared
+	}
+
+	return concluded
+}
+
+// embeddedLicenses returns the license texts and URLs the SBOM provides, by
+// resolved license ID.
+func embeddedLicenses(licenses []LicenseChoice, licenseMap map[string]string) (map[string]string, map[string]string) {
+	var texts, urls map[string]string
+
+	for _, l := range licenses {
+		if l.License == nil {
+			continue
+		}
+
+		text := l.License.Text.decode()
+		u := strings.TrimSpace(l.License.URL)
+
+		if text == "" && u == "" {
+			continue
+		}
+
+		ids := normalizeLicenseIDs([]LicenseChoice{{License: l.License}}, licenseMap)
+		if len(ids) != 1 {
+			continue
+		}
+
+		if text != "" {
+			if texts == nil {
+				texts = map[string]string{}
+			}
+
+			texts[ids[0]] = text
+		}
+
+		if u != "" {
+			if urls == nil {
+				urls = map[string]string{}
+			}
+
+			urls[ids[0]] = u
+		}
+	}
+
+	return texts, urls
+}
+
+// decode returns the attached text, decoding it when base64-encoded.
+func (t *AttachedText) decode() string {
+	if t == nil {
+		return ""
+	}
+
+	if !strings.EqualFold(t.Encoding, "base64") {
+		return strings.TrimSpace(t.Content)
+	}
+
+	b, err := base64.StdEncoding.DecodeString(strings.Join(strings.Fields(t.Content), ""))
+	if err != nil {
+		return ""
+	}
+
+	return strings.TrimSpace(string(b))
+}
+
+// firstLicenseText returns the first text the SBOM embeds for the license among
+// comps.
+func firstLicenseText(comps []OutComponent, licenseID string) string {
+	for _, c := range comps {
+		if t := c.LicenseTexts[licenseID]; t != "" {
+			return t
+		}
+	}
+
+	return ""
+}
+
+// firstLicenseURL returns the first URL the SBOM gives for the license among
+// comps.
+func firstLicenseURL(comps []OutComponent, licenseID string) string {
+	for _, c := range comps {
+		if u := c.LicenseURLs[licenseID]; u != "" {
+			return u
+		}
+	}
+
+	return ""
+}
@@ -10,10 +10,7 @@ import (
 func TestNormalizeLicenseIDs_UseExplicitID(t *testing.T) {
 	t.Parallel()
 
-	licenses := []LicenseChoice{{License: &struct {
-		ID   string `json:"id"`
-		Name string `json:"name"`
-	}{ID: "MIT", Name: "Ignored name"}}}
+	licenses := []LicenseChoice{{License: &License{ID: "MIT", Name: "Ignored name"}}}
 
 	ids := normalizeLicenseIDs(licenses, nil)
 	assert.Equal(t, []string{"MIT"}, ids)
@@ -62,10 +59,7 @@ func TestNormalizeLicenseIDs_LicenseRefForUnknown(t *testing.T) {
 func TestNormalizeLicenseIDs_LicenseRefIDMappedViaLicenseMap(t *testing.T) {
 	t.Parallel()
 
-	licenses := []LicenseChoice{{License: &struct {
-		ID   string `json:"id"`
-		Name string `json:"name"`
-	}{ID: "LicenseRef-MIT-X11"}}}
+	licenses := []LicenseChoice{{License: &License{ID: "LicenseRef-MIT-X11"}}}
 
 	licenseMap := map[string]string{"LicenseRef-MIT-X11": "MIT"}
 
@@ -76,10 +70,7 @@ func TestNormalizeLicenseIDs_LicenseRefIDMappedViaLicenseMap(t *testing.T) {
 func TestNormalizeLicenseIDs_LicenseRefIDWithoutMapping(t *testing.T) {
 	t.Parallel()
 
-	licenses := []LicenseChoice{{License: &struct {
-		ID   string `json:"id"`
-		Name string `json:"name"`
-	}{ID: "LicenseRef-Unknown"}}}
+	licenses := []LicenseChoice{{License: &License{ID: "LicenseRef-Unknown"}}}
 
 	ids := normalizeLicenseIDs(licenses, nil)
 	assert.Equal(t, []string{"LicenseRef-Unknown"}, ids)
@@ -98,10 +89,7 @@ func TestNormalizeLicenseIDs_TroveClassifierOSIApproved(t *testing.T) {
 func TestNormalizeLicenseIDs_TroveClassifierFromLicenseName(t *testing.T) {
 	t.Parallel()
 
-	licenses := []LicenseChoice{{License: &struct {
-		ID   string `json:"id"`
-		Name string `json:"name"`
-	}{Name: "License :: OSI Approved :: BSD License"}}}
+	licenses := []LicenseChoice{{License: &License{Name: "License :: OSI Approved :: BSD License"}}}
 	licenseMap := map[string]string{"BSD License": "BSD-2-Clause"}
 
 	ids := normalizeLicenseIDs(licenses, licenseMap)
@@ -112,14 +100,8 @@ func TestNormalizeLicenseIDs_BareOSIApprovedAlongsideSPDX(t *testing.T) {
 	t.Parallel()
 
 	licenses := []LicenseChoice{
-		{License: &struct {
-			ID   string `json:"id"`
-			Name string `json:"name"`
-		}{ID: "MIT"}},
-		{License: &struct {
-			ID   string `json:"id"`
-			Name string `json:"name"`
-		}{Name: "License :: OSI Approved"}},
+		{License: &License{ID: "MIT"}},
+		{License: &License{Name: "License :: OSI Approved"}},
 	}
 
 	ids := normalizeLicenseIDs(licenses, nil)
@@ -148,60 +130,6 @@ func TestStripTrovePrefix(t *testing.T) {
 	}
 }
 
-func TestMatchLicenseOverride_ExactPURL(t *testing.T) {
-	t.Parallel()
-
-	overrides := map[string]string{
-		"pkg:npm/config-chain@1.1.13": "MIT",
-	}
-
-	assert.Equal(t, "MIT", matchLicenseOverride("pkg:npm/config-chain@1.1.13", overrides))
-	assert.Empty(t, matchLicenseOverride("pkg:npm/config-chain@2.0.0", overrides))
-}
-
-func TestMatchLicenseOverride_PrefixMatch(t *testing.T) {
-	t.Parallel()
-
-	overrides := map[string]string{
-		"pkg:golang/std": "BSD-3-Clause",
-	}
-
-	assert.Equal(t, "BSD-3-Clause", matchLicenseOverride("pkg:golang/std@go1.25.3", overrides))
-	assert.Equal(t, "BSD-3-Clause", matchLicenseOverride("pkg:golang/std@go1.24.0", overrides))
-	assert.Empty(t, matchLicenseOverride("pkg:golang/github.com/foo/bar@v1.0.0", overrides))
-}
-
-func TestMatchLicenseOverride_StripsQualifiers(t *testing.T) {
-	t.Parallel()
-
-	overrides := map[string]string{
-		"pkg:golang/github.com/ghodss/yaml": "MIT",
-	}
-
-	assert.Equal(t, "MIT", matchLicenseOverride("pkg:golang/github.com/ghodss/yaml@v1.0.0?goarch=arm64&goos=darwin&type=module", overrides))
-}
-
-func TestMatchLicenseOverride_SubPackageAndMajorVersion(t *testing.T) {
-	t.Parallel()
-
-	overrides := map[string]string{
-		"pkg:golang/github.com/nrdcg/oci-go-sdk": "UPL-1.0",
-	}
-
-	// Sub-package with embedded major version in path.
-	assert.Equal(t, "UPL-1.0", matchLicenseOverride("pkg:golang/github.com/nrdcg/oci-go-sdk/v65/common@v65.0.0", overrides))
-	// Direct module with version only.
-	assert.Equal(t, "UPL-1.0", matchLicenseOverride("pkg:golang/github.com/nrdcg/oci-go-sdk@v65.0.0", overrides))
-	// Unrelated package must not match.
-	assert.Empty(t, matchLicenseOverride("pkg:golang/github.com/other/pkg@v1.0.0", overrides))
-}
-
-func TestMatchLicenseOverride_NilMap(t *testing.T) {
-	t.Parallel()
-
-	assert.Empty(t, matchLicenseOverride("pkg:npm/foo@1.0.0", nil))
-}
-
 func TestNormalizeLicenseIDs_CompoundExpressionWithParentheses(t *testing.T) {
 	t.Parallel()
 
@@ -257,10 +185,7 @@ func TestNormalizeLicenseIDs_DedupeAndSort(t *testing.T) {
 	licenses := []LicenseChoice{
 		{Expression: "mit"},
 		{Expression: "MIT"},
-		{License: &struct {
-			ID   string `json:"id"`
-			Name string `json:"name"`
-		}{ID: "MIT"}},
+		{License: &License{ID: "MIT"}},
 	}
 
 	known, ok := expression.SPDXLicenseID("MIT")
@@ -269,3 +194,104 @@ func TestNormalizeLicenseIDs_DedupeAndSort(t *testing.T) {
 	ids := normalizeLicenseIDs(licenses, nil)
 	assert.Equal(t, []string{known}, ids)
 }
+
+func TestLicenseExpression(t *testing.T) {
+	t.Parallel()
+
+	licenseMap := map[string]string{"Python Software Foundation License": "PSF-2.0"}
+
+	testCases := []struct {
+		desc     string
+		choices  []LicenseChoice
+		ids      []string
+		expected string
+	}{
+		{
+			desc:     "expression",
+			choices:  []LicenseChoice{{Expression: "MIT or Apache 2.0"}},
+			ids:      []string{"Apache-2.0", "MIT"},
+			expected: "MIT OR Apache-2.0",
+		},
+		{
+			desc:     "exception",
+			choices:  []LicenseChoice{{Expression: "GPL-2.0-only WITH Classpath-exception-2.0"}},
+			ids:      []string{"GPL-2.0-only", "LicenseRef-Classpath-exception-2.0"},
+			expected: "GPL-2.0-only WITH Classpath-exception-2.0",
+		},
+		{
+			desc:     "license map",
+			choices:  []LicenseChoice{{Expression: "Python Software Foundation License"}},
+			ids:      []string{"PSF-2.0"},
+			expected: "PSF-2.0",
+		},
+		{
+			desc:     "several licenses",
+			choices:  []LicenseChoice{{License: &License{ID: "MIT"}}, {License: &License{ID: "ISC"}}},
+			ids:      []string{"ISC", "MIT"},
+			expected: "ISC AND MIT",
+		},
+		{
+			desc:     "single license",
+			choices:  []LicenseChoice{{License: &License{ID: "MIT"}}},
+			ids:      []string{"MIT"},
+			expected: "MIT",
+		},
+		{
+			desc:     "no license",
+			expected: "",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, test.expected, licenseExpression(test.choices, test.ids, licenseMap))
+		})
+	}
+}
+
+func TestSelectLicenses(t *testing.T) {
+	t.Parallel()
+
+	declared := LicenseChoice{License: &License{ID: "MIT", Acknowledgement: "declared"}}
+	concluded := LicenseChoice{Expression: "Apache-2.0", Acknowledgement: "concluded"}
+	unqualified := LicenseChoice{Expression: "BSD-3-Clause"}
+
+	assert.Equal(t, []LicenseChoice{concluded}, selectLicenses([]LicenseChoice{declared, concluded}, false))
+	assert.Equal(t, []LicenseChoice{declared}, selectLicenses([]LicenseChoice{declared, concluded}, true))
+	assert.Equal(t, []LicenseChoice{concluded}, selectLicenses([]LicenseChoice{unqualified, concluded}, false))
+
+	// Without both kinds, every license is kept.
+	assert.Equal(t, []LicenseChoice{declared, unqualified}, selectLicenses([]LicenseChoice{declared, unqualified}, false))
+	assert.Equal(t, []LicenseChoice{concluded}, selectLicenses([]LicenseChoice{concluded}, true))
+}
+
+func TestEmbeddedLicenses(t *testing.T) {
+	t.Parallel()
+
+	licenses := []LicenseChoice{
+		{License: &License{Name: "Acme Proprietary", Text: &AttachedText{Content: "QWNtZSBsaWNlbnNl\n", Encoding: "base64"}, URL: "https://acme.example.com/license"}},
+		{License: &License{ID: "MIT", Text: &AttachedText{Content: "  MIT text  "}}},
+		{License: &License{ID: "Apache-2.0"}},
+		{Expression: "ISC"},
+	}
+
+	texts, urls := embeddedLicenses(licenses, nil)
+	assert.Equal(t, map[string]string{"LicenseRef-Acme-Proprietary": "Acme license", "MIT": "MIT text"}, texts)
+	assert.Equal(t, map[string]string{"LicenseRef-Acme-Proprietary": "https://acme.example.com/license"}, urls)
+
+	texts, urls = embeddedLicenses([]LicenseChoice{{Expression: "MIT"}}, nil)
+	assert.Nil(t, texts)
+	assert.Nil(t, urls)
+}
+
+func TestAttachedTextDecode(t *testing.T) {
+	t.Parallel()
+
+	var nilText *AttachedText
+	assert.Empty(t, nilText.decode())
+	assert.Equal(t, "plain", (&AttachedText{Content: "plain\n"}).decode())
+	assert.Equal(t, "Acme license", (&AttachedText{Content: "QWNtZSBsaWNlbnNl", Encoding: "BASE64"}).decode())
+	assert.Empty(t, (&AttachedText{Content: "not base64!", Encoding: "base64"}).decode())
+}
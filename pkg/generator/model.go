@@ -1,68 +1,321 @@
 package generator
 
-import "regexp"
+import (
+	"regexp"
+	"slices"
+
+	"github.com/aquasecurity/trivy/pkg/licensing/expression"
+)
 
 // SBOM represents a CycloneDX SBOM structure.
 type SBOM struct {
-	Components []Component `json:"components"`
+	SpecVersion  string       `json:"specVersion"`
+	SerialNumber string       `json:"serialNumber"`
+	Metadata     Metadata     `json:"metadata"`
+	Components   []Component  `json:"components"`
+	Dependencies []Dependency `json:"dependencies"`
+}
+
+// Dependency represents the direct dependencies of the component referenced
+// by Ref, as bom-refs.
+type Dependency struct {
+	Ref       string   `json:"ref"`
+	DependsOn []string `json:"dependsOn"`
+}
+
+// Metadata describes the SBOM subject.
+type Metadata struct {
+	Component *Component            `json:"component"`
+	Supplier  *OrganizationalEntity `json:"supplier"`
 }
 
 // Component represents a component in the SBOM.
 type Component struct {
+	BOMRef    string `json:"bom-ref"`
+	Type      string `json:"type"`
+	Group     string `json:"group"`
 	Name      string `json:"name"`
 	Version   string `json:"version"`
+	Scope     string `json:"scope"`
 	PURL      string `json:"purl"`
 	Copyright string `json:"copyright"`
-	Supplier  *struct {
-		Name string `json:"name"`
-	} `json:"supplier"`
-	Licenses []LicenseChoice `json:"licenses"`
+	// Author is the CycloneDX 1.5 free-form author, superseded by Authors.
+	Author             string                  `json:"author"`
+	Authors            []OrganizationalContact `json:"authors"`
+	Publisher          string                  `json:"publisher"`
+	Manufacturer       *OrganizationalEntity   `json:"manufacturer"`
+	Supplier           *OrganizationalEntity   `json:"supplier"`
+	ExternalReferences []ExternalReference     `json:"externalReferences"`
+	Licenses           []LicenseChoice         `json:"licenses"`
+	Evidence           *Evidence               `json:"evidence"`
+	Properties         []Property              `json:"properties"`
+}
+
+// ExtraComponent declares a component that is not in the SBOM, such as
+// vendored C sources, fonts, icons or copied code snippets.
+type ExtraComponent struct {
+	Name    string `json:"name" yaml:"name"`
+	Version string `json:"version,omitempty" yaml:"version,omitempty"`
+	PURL    string `json:"purl,omitempty" yaml:"purl,omitempty"`
+	// Type is the CycloneDX component type, "library" when empty.
+	Type string `json:"type,omitempty" yaml:"type,omitempty"`
+	// License is an SPDX license expression.
+	License   string `json:"license" yaml:"license"`
+	Copyright string `json:"copyright,omitempty" yaml:"copyright,omitempty"`
+	URL       string `json:"url,omitempty" yaml:"url,omitempty"`
+	// LicenseFile is the path to the license text of the component, relative to
+	// the file declaring it. It requires License to be a single license.
+	LicenseFile string `json:"licenseFile,omitempty" yaml:"licenseFile,omitempty"`
+}
+
+// OrganizationalEntity represents an organization, such as the supplier or the
+// manufacturer of a component.
+type OrganizationalEntity struct {
+	Name string   `json:"name"`
+	URL  []string `json:"url"`
+}
+
+// OrganizationalContact represents an individual, such as an author of a
+// component.
+type OrganizationalContact struct {
+	Name  string `json:"name"`
+	Email string `json:"email"`
+}
+
+// ExternalReference represents a link to a resource about a component, such as
+// its source repository ("vcs") or home page ("website").
+type ExternalReference struct {
+	Type string `json:"type"`
+	URL  string `json:"url"`
+}
+
+// LicenseCorrection corrects the license of the versions of a package matching
+// Versions (all versions when empty). Justification is mandatory; Reference
+// points to the evidence, such as the upstream LICENSE file.
+type LicenseCorrection struct {
+	Versions      string `json:"versions,omitempty" yaml:"versions,omitempty"`
+	License       string `json:"license" yaml:"license"`
+	Justification string `json:"justification" yaml:"justification"`
+	Reference     string `json:"reference,omitempty" yaml:"reference,omitempty"`
+}
+
+// LicenseCorrections lists the corrections of a package; the first one
+// matching the version applies. In the data files, a single correction can be
+// given as an object instead of a list.
+type LicenseCorrections []LicenseCorrection
+
+// AppliedCorrection records the license correction applied to a component.
+type AppliedCorrection struct {
+	LicenseCorrection
+
+	// PURL is the key of the correction in the license corrections.
+	PURL string `json:"purl"`
+}
+
+// Curation overrides the metadata of the components matching its PURL key.
+// Empty fields keep the metadata found in the SBOM and the package caches.
+type Curation struct {
+	// Name replaces the component display name.
+	Name string `json:"name,omitempty" yaml:"name,omitempty"`
+	// URL replaces the upstream URL of the component.
+	URL string `json:"url,omitempty" yaml:"url,omitempty"`
+	// Copyright replaces the copyright notices of the component, one per line.
+	Copyright []string `json:"copyright,omitempty" yaml:"copyright,omitempty"`
+	// Notes are free-form notes rendered along with the component.
+	Notes []string `json:"notes,omitempty" yaml:"notes,omitempty"`
+	// Modifications state that the component was modified, as required by
+	// Apache-2.0 section 4(b), e.g. "Modified by Traefik Labs to support
+	// HTTP/3.".
+	Modifications []string `json:"modifications,omitempty" yaml:"modifications,omitempty"`
+}
+
+// Property represents a CycloneDX name-value property, such as
+// "cdx:npm:package:development".
+type Property struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
 }
 
-// Filters holds compiled regex patterns for excluding components.
+// Filters holds the rules for excluding components. Components matching an
+// Include rule are kept even when an exclusion rule matches them.
 type Filters struct {
-	PURLRegex []*regexp.Regexp `json:"purlRegex"`
-	Suppliers []*regexp.Regexp `json:"suppliers"`
+	FilterRules `yaml:",inline"`
+
+	Include FilterRules `json:"include" yaml:"include,omitempty"`
+
+	// firstParty identifies the components of the project itself.
+	firstParty firstParty
+}
+
+// FilterRules holds rules selecting components: a component is selected when
+// any rule matches it.
+type FilterRules struct {
+	PURLRegex []*regexp.Regexp `json:"purlRegex" yaml:"purlRegex,omitempty"`
+	Suppliers []*regexp.Regexp `json:"suppliers" yaml:"suppliers,omitempty"`
+	// Scopes and Types are matched case-insensitively against the CycloneDX
+	// scope (a component without scope is "required") and type.
+	Scopes     []string         `json:"scopes" yaml:"scopes,omitempty"`
+	Types      []string         `json:"types" yaml:"types,omitempty"`
+	Groups     []*regexp.Regexp `json:"groups" yaml:"groups,omitempty"`
+	Licenses   []*regexp.Regexp `json:"licenses" yaml:"licenses,omitempty"`
+	Properties []PropertyFilter `json:"properties" yaml:"properties,omitempty"`
+}
+
+// PropertyFilter matches components having a property named Name whose value
+// matches Value, or any value when Value is nil.
+type PropertyFilter struct {
+	Name  string         `json:"name" yaml:"name"`
+	Value *regexp.Regexp `json:"value" yaml:"value,omitempty"`
 }
 
 // LicenseChoice represents a license in a component.
 type LicenseChoice struct {
 	Expression string `json:"expression"`
-	License    *struct {
-		ID   string `json:"id"`
-		Name string `json:"name"`
-	} `json:"license"`
+	// Acknowledgement is "declared" or "concluded" (CycloneDX 1.6). For a
+	// single license, it is set on License instead.
+	Acknowledgement string   `json:"acknowledgement"`
+	License         *License `json:"license"`
+}
+
+// License represents a single license, identified by its SPDX ID or its name.
+// Custom licenses may embed their text or link to it.
+type License struct {
+	ID              string        `json:"id"`
+	Name            string        `json:"name"`
+	Acknowledgement string        `json:"acknowledgement"`
+	Text            *AttachedText `json:"text"`
+	URL             string        `json:"url"`
+}
+
+// AttachedText represents text embedded in the SBOM, possibly base64-encoded.
+type AttachedText struct {
+	ContentType string `json:"contentType"`
+	Encoding    string `json:"encoding"`
+	Content     string `json:"content"`
+}
+
+// Evidence represents what the SBOM generator found in the component files.
+type Evidence struct {
+	Licenses  []LicenseChoice `json:"licenses"`
+	Copyright []struct {
+		Text string `json:"text"`
+	} `json:"copyright"`
 }
 
+// License sources recorded in OutComponent.LicenseSource.
+const (
+	licenseSourceSBOM       = "sbom"
+	licenseSourceEvidence   = "evidence"
+	licenseSourceMetadata   = "metadata"
+	licenseSourceCorrection = "correction"
+	licenseSourceDetected   = "detected"
+)
+
 // OutComponent represents a component in the output model.
 type OutComponent struct {
-	Name       string
-	Version    string
-	PURL       string
-	URL        string
-	LicenseIDs []string
-	Copyright  string
+	Name    string `json:"name"`
+	Version string `json:"version,omitempty"`
+	PURL    string `json:"purl,omitempty"`
+	URL     string `json:"url,omitempty"`
+	// DocumentationURL links the documentation of the component, such as the
+	// docs.rs page of a crate.
+	DocumentationURL string   `json:"documentationUrl,omitempty"`
+	LicenseIDs       []string `json:"licenseIds"`
+	// LicenseExpression is the SPDX license expression of the component, e.g.
+	// "MIT OR Apache-2.0", or its LicenseIDs combined with AND.
+	LicenseExpression string `json:"licenseExpression,omitempty"`
+	// LicenseSource records where LicenseIDs come from: "sbom", "evidence" (SBOM
+	// license evidence), "metadata" (package metadata such as Cargo.toml or
+	// pom.xml), "correction" or "detected".
+	LicenseSource string `json:"licenseSource,omitempty"`
+	// Correction is the license correction applied to the component, if any.
+	Correction *AppliedCorrection `json:"correction,omitempty"`
+	Copyright  string             `json:"copyright,omitempty"`
+	// CopyrightSource records where Copyright was found (e.g. "sbom",
+	// "LICENSE", "package.json contributors").
+	CopyrightSource string `json:"copyrightSource,omitempty"`
+	// LicenseTexts and LicenseURLs hold the license texts and URLs the SBOM
+	// provides for the component, by license ID.
+	LicenseTexts map[string]string `json:"licenseTexts,omitempty"`
+	LicenseURLs  map[string]string `json:"licenseUrls,omitempty"`
+	// NoticeTexts are the NOTICE files shipped with the component, which the
+	// Apache License requires reproducing in the attribution notices.
+	NoticeTexts []string `json:"noticeTexts,omitempty"`
+	// Notes and Modifications come from the curation of the component.
+	Notes         []string `json:"notes,omitempty"`
+	Modifications []string `json:"modifications,omitempty"`
+	// Curation is the key of the curation applied to the component, if any.
+	Curation string `json:"curation,omitempty"`
 }
 
 // LicenseBlock represents a license block in the output model.
 type LicenseBlock struct {
-	ID     string
-	Name   string
-	Text   string
-	UsedBy []OutComponent
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	// Anchor uniquely identifies the block: the license ID for the SPDX text,
+	// or the license ID followed by TextHash for a text shipped by components.
+	Anchor string `json:"anchor"`
+	// TextHash is the hash of the text shipped by the components of the block,
+	// empty when Text is the SPDX reference text.
+	TextHash string `json:"textHash,omitempty"`
+	Text     string `json:"text"`
+	// URL links to the license, when the SBOM provides one.
+	URL    string         `json:"url,omitempty"`
+	UsedBy []OutComponent `json:"usedBy"`
 }
 
 // OverviewItem represents an overview item in the output model.
 type OverviewItem struct {
-	ID    string
-	Name  string
-	Count int
+	ID     string `json:"id"`
+	Name   string `json:"name"`
+	Anchor string `json:"anchor"`
+	Count  int    `json:"count"`
+}
+
+// ComponentGroup represents the components of an ecosystem, as returned by
+// the groupByEcosystem template function.
+type ComponentGroup struct {
+	Ecosystem  string         `json:"ecosystem"`
+	Components []OutComponent `json:"components"`
 }
 
 // Model represents the data model for the output.
 type Model struct {
-	GeneratedAt string
-	Overview    []OverviewItem
-	Licenses    []LicenseBlock
-	Notices     []OutComponent
+	RepoName    string         `json:"repoName,omitempty"`
+	GeneratedAt string         `json:"generatedAt"`
+	Overview    []OverviewItem `json:"overview"`
+	Licenses    []LicenseBlock `json:"licenses"`
+	Notices     []OutComponent `json:"notices"`
+	// Components lists all the components, including the ones without notice.
+	Components []OutComponent `json:"components"`
+
+	// byKey holds the components by componentKey, for the SBOM outputs.
+	byKey map[string]OutComponent
+}
+
+// LicenseURL returns a URL of the license text of the component c: the one the
+// SBOM gives for the component, the one of its license block, the component URL,
+// or the SPDX license page. It is empty for a custom license without URL.
+func (m Model) LicenseURL(c OutComponent, licenseID string) string {
+	if u := c.LicenseURLs[licenseID]; u != "" {
+		return u
+	}
+
+	key := outComponentKey(c)
+
+	for _, l := range m.Licenses {
+		if l.ID == licenseID && l.URL != "" && slices.ContainsFunc(l.UsedBy, func(u OutComponent) bool { return outComponentKey(u) == key }) {
+			return l.URL
+		}
+	}
+
+	if c.URL != "" {
+		return c.URL
+	}
+
+	if _, ok := expression.SPDXLicenseID(licenseID); ok {
+		return "https://spdx.org/licenses/" + licenseID + ".html"
+	}
+
+	return ""
 }
@@ -0,0 +1,175 @@
+package generator
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Template engines of the outputs.
+const (
+	engineHTML = "html"
+	engineText = "text"
+)
+
+// builtinTemplate is a template embedded in assimilis.
+type builtinTemplate struct {
+	file   string
+	engine string
+}
+
+// builtinTemplates are the embedded templates, by name.
+var builtinTemplates = map[string]builtinTemplate{
+	"html":          {file: embeddedHTMLTemplate, engine: engineHTML},
+	"notice":        {file: embeddedNoticeTemplate, engine: engineText},
+	"json":          {file: "model.json.gotpl", engine: engineText},
+	"markdown":      {file: "markdown.gotpl", engine: engineText},
+	"text":          {file: "text.gotpl", engine: engineText},
+	"apache-notice": {file: "apache_notice.gotpl", engine: engineText},
+	"all-in-one":    {file: "all_in_one.gotpl", engine: engineText},
+	"csv":           {file: "components.csv.gotpl", engine: engineText},
+	"tsv":           {file: "components.tsv.gotpl", engine: engineText},
+	"go-licenses":   {file: "go_licenses.csv.gotpl", engine: engineText},
+}
+
+// builtinWriters are the built-in outputs written by code instead of a
+// template, by name.
+var builtinWriters = map[string]func(in inputs, model Model) (string, error){
+	"cyclonedx":        writeCycloneDX,
+	"spdx":             writeSPDX,
+	"ios-settings":     writeIOSSettings,
+	"android-licenses": writeAndroidLicenses,
+	"android-metadata": writeAndroidMetadata,
+	"electron":         writeElectron,
+}
+
+// BuiltinTemplateNames returns the names of the built-in templates and
+// writers.
+func BuiltinTemplateNames() []string {
+	names := make([]string, 0, len(builtinTemplates)+len(builtinWriters))
+	for name := range builtinTemplates {
+		names = append(names, name)
+	}
+
+	for name := range builtinWriters {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
+
+// isBuiltin reports whether name is a built-in template or writer.
+func isBuiltin(name string) bool {
+	_, template := builtinTemplates[name]
+	_, writer := builtinWriters[name]
+
+	return template || writer
+}
+
+// ParseOutput parses an output given as TEMPLATE=PATH, e.g. "notice=NOTICE.md"
+// or "docs/licenses.md.gotpl=docs/licenses.md".
+func ParseOutput(s string) (Output, error) {
+	tpl, path, ok := strings.Cut(s, "=")
+	if !ok {
+		return Output{}, fmt.Errorf("invalid output %q: expected TEMPLATE=PATH", s)
+	}
+
+	o := Output{Template: strings.TrimSpace(tpl), Path: strings.TrimSpace(path)}
+	if err := o.validate(); err != nil {
+		return Output{}, err
+	}
+
+	return o, nil
+}
+
+// outputs returns the outputs to render: the configured ones, or the HTML and
+// NOTICE files.
+func (c Config) outputs() []Output {
+	if len(c.Outputs) > 0 {
+		return c.Outputs
+	}
+
+	htmlTemplate, noticeTemplate := "html", "notice"
+
+	if c.HTMLTemplatePath != "" {
+		htmlTemplate = c.HTMLTemplatePath
+	}
+
+	if c.NoticeTplPath != "" {
+		noticeTemplate = c.NoticeTplPath
+	}
+
+	return []Output{
+		{Template: htmlTemplate, Path: c.HTMLFileName, Engine: engineHTML},
+		{Template: noticeTemplate, Path: c.NoticeFileName, Engine: engineText},
+	}
+}
+
+// validateOutputs checks the outputs, and that no two outputs have the same
+// path.
+func validateOutputs(outputs []Output) error {
+	var errs []error
+
+	paths := map[string]bool{}
+
+	for _, o := range outputs {
+		if err := o.validate(); err != nil {
+			errs = append(errs, err)
+
+			continue
+		}
+
+		path := filepath.Clean(o.Path)
+		if paths[path] {
+			errs = append(errs, fmt.Errorf("output %q: path used by several outputs", o.Path))
+		}
+
+		paths[path] = true
+	}
+
+	return errors.Join(errs...)
+}
+
+func (o Output) validate() error {
+	switch {
+	case o.Template == "":
+		return fmt.Errorf("output %q: missing template", o.Path)
+	case o.Path == "":
+		return fmt.Errorf("output %q: missing path", o.Template)
+	case o.Engine != "" && o.Engine != engineHTML && o.Engine != engineText:
+		return fmt.Errorf("output %q: unknown engine %q, expected %q or %q", o.Path, o.Engine, engineHTML, engineText)
+	}
+
+	return nil
+}
+
+// engine returns the template engine of the output.
+func (o Output) engine() string {
+	if o.Engine != "" {
+		return o.Engine
+	}
+
+	if builtin, ok := builtinTemplates[o.Template]; ok {
+		return builtin.engine
+	}
+
+	switch strings.ToLower(filepath.Ext(o.Path)) {
+	case ".html", ".htm":
+		return engineHTML
+	default:
+		return engineText
+	}
+}
+
+// path returns the path of the output file, resolved against outDir.
+func (o Output) path(outDir string) string {
+	if filepath.IsAbs(o.Path) {
+		return o.Path
+	}
+
+	return filepath.Join(outDir, o.Path)
+}
@@ -0,0 +1,92 @@
+package generator
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseOutput(t *testing.T) {
+	t.Parallel()
+
+	o, err := ParseOutput("notice=NOTICE.md")
+	require.NoError(t, err)
+	assert.Equal(t, Output{Template: "notice", Path: "NOTICE.md"}, o)
+
+	o, err = ParseOutput(" docs/licenses.md.gotpl = ../docs/licenses.md ")
+	require.NoError(t, err)
+	assert.Equal(t, Output{Template: "docs/licenses.md.gotpl", Path: "../docs/licenses.md"}, o)
+
+	for _, s := range []string{"notice", "=NOTICE.md", "notice="} {
+		_, err := ParseOutput(s)
+		assert.Error(t, err, s)
+	}
+}
+
+func TestBuiltinTemplateNames(t *testing.T) {
+	t.Parallel()
+
+	names := BuiltinTemplateNames()
+
+	assert.Contains(t, names, "html")
+	assert.Contains(t, names, "cyclonedx")
+	assert.Contains(t, names, "spdx")
+	assert.Contains(t, names, "ios-settings")
+	assert.IsNonDecreasing(t, names)
+}
+
+func TestConfig_Outputs(t *testing.T) {
+	t.Parallel()
+
+	cfg := DefaultConfig()
+	assert.Equal(t, []Output{
+		{Template: "html", Path: defaultHTMLFileName, Engine: engineHTML},
+		{Template: "notice", Path: defaultNoticeFileName, Engine: engineText},
+	}, cfg.outputs())
+
+	cfg.HTMLTemplatePath = "branded.gotpl"
+	cfg.NoticeFileName = "NOTICE.txt"
+	assert.Equal(t, []Output{
+		{Template: "branded.gotpl", Path: defaultHTMLFileName, Engine: engineHTML},
+		{Template: "notice", Path: "NOTICE.txt", Engine: engineText},
+	}, cfg.outputs())
+
+	cfg.Outputs = []Output{{Template: "json", Path: "licenses.json"}}
+	assert.Equal(t, cfg.Outputs, cfg.outputs())
+}
+
+func TestValidateOutputs(t *testing.T) {
+	t.Parallel()
+
+	require.NoError(t, validateOutputs([]Output{{Template: "html", Path: "a.html"}, {Template: "notice", Path: "NOTICE.md"}}))
+
+	err := validateOutputs([]Output{
+		{Template: "html", Path: "a.html"},
+		{Template: "notice", Path: "./a.html"},
+		{Path: "b.txt"},
+		{Template: "notice", Path: "c.txt", Engine: "markdown"},
+	})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `output "./a.html": path used by several outputs`)
+	assert.Contains(t, err.Error(), `output "b.txt": missing template`)
+	assert.Contains(t, err.Error(), `output "c.txt": unknown engine "markdown"`)
+}
+
+func TestOutput_Engine(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, engineHTML, Output{Template: "html", Path: "licenses.txt"}.engine())
+	assert.Equal(t, engineText, Output{Template: "json", Path: "licenses.json"}.engine())
+	assert.Equal(t, engineHTML, Output{Template: "page.gotpl", Path: "licenses.HTML"}.engine())
+	assert.Equal(t, engineText, Output{Template: "page.gotpl", Path: "licenses.md"}.engine())
+	assert.Equal(t, engineText, Output{Template: "page.gotpl", Path: "licenses.html", Engine: engineText}.engine())
+}
+
+func TestOutput_Path(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, filepath.Join("third_party", "NOTICE.md"), Output{Path: "NOTICE.md"}.path("third_party"))
+	assert.Equal(t, "/tmp/NOTICE.md", Output{Path: "/tmp/NOTICE.md"}.path("third_party"))
+}
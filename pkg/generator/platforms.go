@@ -0,0 +1,229 @@
+package generator
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// platformEntry is a component with its licenses, as listed by the platform
+// license formats.
+type platformEntry struct {
+	// Title is the name of the component, followed by its version when
+	// several components have the same name.
+	Title     string
+	Component OutComponent
+	Licenses  []LicenseBlock
+}
+
+// platformEntries returns the components of the model having a copyright or a
+// license text, with their license blocks.
+func platformEntries(model Model) []platformEntry {
+	byComponent := map[string][]LicenseBlock{}
+
+	for _, l := range model.Licenses {
+		for _, c := range l.UsedBy {
+			key := outComponentKey(c)
+
+			if !slices.ContainsFunc(byComponent[key], func(b LicenseBlock) bool { return b.Anchor == l.Anchor }) {
+				byComponent[key] = append(byComponent[key], l)
+			}
+		}
+	}
+
+	names := map[string]int{}
+	for _, c := range model.Components {
+		names[c.Name]++
+	}
+
+	var entries []platformEntry
+
+	for _, c := range model.Components {
+		title := c.Name
+		if names[c.Name] > 1 && c.Version != "" {
+			title += " " + c.Version
+		}
+
+		e := platformEntry{Title: title, Component: c, Licenses: byComponent[outComponentKey(c)]}
+		if e.text() != "" {
+			entries = append(entries, e)
+		}
+	}
+
+	return entries
+}
+
+// outComponentKey identifies a component of the model, like componentKey.
+func outComponentKey(c OutComponent) string {
+	if c.PURL != "" {
+		return c.PURL
+	}
+
+	return c.Name + "@" + c.Version
+}
+
+// licenseText returns the texts of the licenses of the entry.
+func (e platformEntry) licenseText() string {
+	texts := make([]string, 0, len(e.Licenses))
+
+	for _, l := range e.Licenses {
+		if text := strings.TrimSpace(l.Text); text != "" {
+			texts = append(texts, text)
+		}
+	}
+
+	return strings.Join(texts, "\n\n")
+}
+
+// text returns the copyright and the license texts of the entry.
+func (e platformEntry) text() string {
+	copyright := strings.TrimSpace(e.Component.Copyright)
+
+	switch text := e.licenseText(); {
+	case copyright == "":
+		return text
+	case text == "":
+		return copyright
+	default:
+		return copyright + "\n\n" + text
+	}
+}
+
+// license returns the license expression of the entry.
+func (e platformEntry) license() string {
+	if e.Component.LicenseExpression != "" {
+		return e.Component.LicenseExpression
+	}
+
+	return strings.Join(e.Component.LicenseIDs, " AND ")
+}
+
+// writeIOSSettings returns the acknowledgements of an iOS Settings.bundle, a
+// property list with a group per component, as written by CocoaPods.
+func writeIOSSettings(_ inputs, model Model) (string, error) {
+	var b strings.Builder
+
+	b.WriteString(xml.Header)
+	b.WriteString(`<!DOCTYPE plist PUBLIC "-//Apple//DTD PLIST 1.0//EN" "http://www.apple.com/DTDs/PropertyList-1.0.dtd">` + "\n")
+	b.WriteString("<plist version=\"1.0\">\n<dict>\n\t<key>PreferenceSpecifiers</key>\n\t<array>\n")
+
+	writeGroup := func(title, license, footer string) {
+		b.WriteString("\t\t<dict>\n")
+		fmt.Fprintf(&b, "\t\t\t<key>FooterText</key>\n\t\t\t<string>%s</string>\n", escapeXML(footer))
+
+		if license != "" {
+			fmt.Fprintf(&b, "\t\t\t<key>License</key>\n\t\t\t<string>%s</string>\n", escapeXML(license))
+		}
+
+		fmt.Fprintf(&b, "\t\t\t<key>Title</key>\n\t\t\t<string>%s</string>\n", escapeXML(title))
+		b.WriteString("\t\t\t<key>Type</key>\n\t\t\t<string>PSGroupSpecifier</string>\n")
+		b.WriteString("\t\t</dict>\n")
+	}
+
+	writeGroup("Acknowledgements", "", "This application makes use of the following third party libraries:")
+
+	for _, e := range platformEntries(model) {
+		writeGroup(e.Title, e.license(), e.text())
+	}
+
+	b.WriteString("\t</array>\n\t<key>StringsTable</key>\n\t<string>Acknowledgements</string>\n")
+	b.WriteString("\t<key>Title</key>\n\t<string>Acknowledgements</string>\n</dict>\n</plist>\n")
+
+	return b.String(), nil
+}
+
+// xmlEscaper escapes the characters having a meaning in XML text.
+var xmlEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+
+// escapeXML escapes s for an XML element. The characters XML 1.0 does not
+// allow are removed, except the form feeds of some license texts, which become
+// line breaks.
+func escapeXML(s string) string {
+	s = strings.Map(func(r rune) rune {
+		switch {
+		case r == '\t' || r == '\n' || r == '\r':
+			return r
+		case r == '\f':
+			return '\n'
+		case r < 0x20 || r >= 0xd800 && r < 0xe000 || r == 0xfffe || r == 0xffff:
+			return -1
+		default:
+			return r
+		}
+	}, s)
+
+	return xmlEscaper.Replace(s)
+}
+
+// androidLicenses returns the third_party_licenses and
+// third_party_license_metadata raw resources of the OSS licenses Gradle plugin:
+// the texts of the components, each followed by a line break, and a line
+// "offset:length title" per component, in bytes.
+func androidLicenses(model Model) (string, string) {
+	var licenses, metadata strings.Builder
+
+	for _, e := range platformEntries(model) {
+		text := e.text()
+
+		fmt.Fprintf(&metadata, "%d:%d %s\n", licenses.Len(), len(text), e.Title)
+
+		licenses.WriteString(text)
+		licenses.WriteString("\n")
+	}
+
+	return licenses.String(), metadata.String()
+}
+
+// writeAndroidLicenses returns the third_party_licenses raw resource.
+func writeAndroidLicenses(_ inputs, model Model) (string, error) {
+	licenses, _ := androidLicenses(model)
+
+	return licenses, nil
+}
+
+// writeAndroidMetadata returns the third_party_license_metadata raw resource.
+func writeAndroidMetadata(_ inputs, model Model) (string, error) {
+	_, metadata := androidLicenses(model)
+
+	return metadata, nil
+}
+
+// electronLicense is a component in the JSON of license-checker, which Electron
+// apps commonly render in their about window.
+type electronLicense struct {
+	Licenses    string `json:"licenses"`
+	Repository  string `json:"repository,omitempty"`
+	PURL        string `json:"purl,omitempty"`
+	Copyright   string `json:"copyright,omitempty"`
+	LicenseText string `json:"licenseText,omitempty"`
+}
+
+// writeElectron returns the components as the JSON of license-checker, by
+// "name@version".
+func writeElectron(_ inputs, model Model) (string, error) {
+	licenses := map[string]electronLicense{}
+
+	for _, e := range platformEntries(model) {
+		key := e.Component.Name
+		if e.Component.Version != "" {
+			key += "@" + e.Component.Version
+		}
+
+		licenses[key] = electronLicense{
+			Licenses:    e.license(),
+			Repository:  e.Component.URL,
+			PURL:        e.Component.PURL,
+			Copyright:   strings.TrimSpace(e.Component.Copyright),
+			LicenseText: e.licenseText(),
+		}
+	}
+
+	b, err := json.MarshalIndent(licenses, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to encode licenses: %w", err)
+	}
+
+	return string(b) + "\n", nil
+}
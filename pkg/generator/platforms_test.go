@@ -0,0 +1,108 @@
+package generator
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testPlatformModel returns a model with two versions of foo, a component
+// without license text nor copyright, and a license text with a form feed.
+func testPlatformModel() Model {
+	foo1 := OutComponent{Name: "foo", Version: "1.0.0", PURL: "pkg:npm/foo@1.0.0", LicenseIDs: []string{"MIT"}, LicenseExpression: "MIT", Copyright: "Copyright (c) Foo <foo@example.com>"}
+	foo2 := OutComponent{Name: "foo", Version: "2.0.0", PURL: "pkg:npm/foo@2.0.0", LicenseIDs: []string{"Apache-2.0", "MIT"}, LicenseExpression: "MIT OR Apache-2.0", URL: "https://example.com/foo"}
+	empty := OutComponent{Name: "empty", Version: "1.0.0"}
+
+	return Model{
+		Licenses: []LicenseBlock{
+			{ID: "Apache-2.0", Anchor: "Apache-2.0", Text: "Apache text.\fEnd.", UsedBy: []OutComponent{foo2}},
+			{ID: "MIT", Anchor: "MIT", Text: "MIT text.\n", UsedBy: []OutComponent{foo1, foo2, foo2}},
+		},
+		Components: []OutComponent{empty, foo1, foo2},
+	}
+}
+
+func TestPlatformEntries(t *testing.T) {
+	t.Parallel()
+
+	entries := platformEntries(testPlatformModel())
+
+	require.Len(t, entries, 2)
+	assert.Equal(t, "foo 1.0.0", entries[0].Title)
+	assert.Equal(t, "Copyright (c) Foo <foo@example.com>\n\nMIT text.", entries[0].text())
+	assert.Equal(t, "foo 2.0.0", entries[1].Title)
+	assert.Equal(t, "Apache text.\fEnd.\n\nMIT text.", entries[1].text())
+	assert.Equal(t, "MIT OR Apache-2.0", entries[1].license())
+}
+
+func TestWriteIOSSettings(t *testing.T) {
+	t.Parallel()
+
+	out, err := writeIOSSettings(inputs{}, testPlatformModel())
+	require.NoError(t, err)
+
+	// The property list is valid XML; its strings are the values of the dicts
+	// after their keys, sorted.
+	var plist struct {
+		Dict struct {
+			Array struct {
+				Dicts []struct {
+					Keys    []string `xml:"key"`
+					Strings []string `xml:"string"`
+				} `xml:"dict"`
+			} `xml:"array"`
+			Strings []string `xml:"string"`
+		} `xml:"dict"`
+	}
+	require.NoError(t, xml.Unmarshal([]byte(out), &plist))
+
+	assert.Equal(t, []string{"Acknowledgements", "Acknowledgements"}, plist.Dict.Strings)
+
+	groups := plist.Dict.Array.Dicts
+	require.Len(t, groups, 3)
+	assert.Equal(t, []string{"FooterText", "License", "Title", "Type"}, groups[1].Keys)
+	assert.Equal(t, []string{"Copyright (c) Foo <foo@example.com>\n\nMIT text.", "MIT", "foo 1.0.0", "PSGroupSpecifier"}, groups[1].Strings)
+	assert.Equal(t, "Apache text.\nEnd.\n\nMIT text.", groups[2].Strings[0])
+}
+
+func TestAndroidLicenses(t *testing.T) {
+	t.Parallel()
+
+	licenses, metadata := androidLicenses(testPlatformModel())
+
+	lines := strings.Split(strings.TrimSuffix(metadata, "\n"), "\n")
+	require.Equal(t, []string{"0:46 foo 1.0.0", "47:28 foo 2.0.0"}, lines)
+
+	for i, title := range []string{"foo 1.0.0", "foo 2.0.0"} {
+		position, name, _ := strings.Cut(lines[i], " ")
+		assert.Equal(t, title, name)
+
+		offset, length, _ := strings.Cut(position, ":")
+		start, err := strconv.Atoi(offset)
+		require.NoError(t, err)
+		n, err := strconv.Atoi(length)
+		require.NoError(t, err)
+
+		assert.Equal(t, platformEntries(testPlatformModel())[i].text(), licenses[start:start+n])
+	}
+}
+
+func TestWriteElectron(t *testing.T) {
+	t.Parallel()
+
+	out, err := writeElectron(inputs{}, testPlatformModel())
+	require.NoError(t, err)
+
+	var licenses map[string]electronLicense
+	require.NoError(t, json.Unmarshal([]byte(out), &licenses))
+
+	assert.Equal(t, map[string]electronLicense{
+		"foo@1.0.0": {Licenses: "MIT", PURL: "pkg:npm/foo@1.0.0", Copyright: "Copyright (c) Foo <foo@example.com>", LicenseText: "MIT text."},
+		"foo@2.0.0": {Licenses: "MIT OR Apache-2.0", Repository: "https://example.com/foo", PURL: "pkg:npm/foo@2.0.0", LicenseText: "Apache text.\fEnd.\n\nMIT text."},
+	}, licenses)
+}
@@ -1,49 +1,351 @@
 package generator
 
 import (
-	"regexp"
+	"net/url"
+	"sort"
 	"strings"
 )
 
-var purlRegex = regexp.MustCompile(`^pkg:([^/]+)/(.+)@([^@]+)$`)
+// packageURL is a parsed package URL
+// (https://github.com/package-url/purl-spec/blob/main/PURL-SPECIFICATION.rst).
+// Components are percent-decoded, but unlike the reference implementation
+// their case is kept as-is: Go module paths and registry directory names are
+// case-sensitive on disk.
+type packageURL struct {
+	Type       string
+	Namespace  string
+	Name       string
+	Version    string
+	Qualifiers map[string]string
+	Subpath    string
+}
+
+// parsePURL parses purl following the package-url specification. It reports
+// false when purl is not a valid package URL.
+func parsePURL(purl string) (packageURL, bool) {
+	var p packageURL
+
+	rest := strings.TrimSpace(purl)
+
+	if idx := strings.LastIndex(rest, "#"); idx != -1 {
+		p.Subpath = decodePURLSegments(rest[idx+1:], true)
+		rest = rest[:idx]
+	}
+
+	if idx := strings.LastIndex(rest, "?"); idx != -1 {
+		p.Qualifiers = parsePURLQualifiers(rest[idx+1:])
+		rest = rest[:idx]
+	}
+
+	scheme, rest, ok := strings.Cut(rest, ":")
+	if !ok || !strings.EqualFold(scheme, "pkg") {
+		return packageURL{}, false
+	}
+
+	rest = strings.Trim(rest, "/")
+
+	typ, rest, ok := strings.Cut(rest, "/")
+	if !ok || typ == "" {
+		return packageURL{}, false
+	}
+
+	p.Type = strings.ToLower(typ)
+
+	// The version separator is the last "@" after the last "/": an "@" in the
+	// namespace (such as an unencoded npm scope) is not a version separator.
+	if idx := strings.LastIndex(rest, "@"); idx != -1 && idx > strings.LastIndex(rest, "/") {
+		p.Version = unescapePURL(rest[idx+1:])
+		rest = rest[:idx]
+	}
+
+	rest = strings.TrimRight(rest, "/")
+
+	if idx := strings.LastIndex(rest, "/"); idx != -1 {
+		p.Namespace = decodePURLSegments(rest[:idx], false)
+		rest = rest[idx+1:]
+	}
+
+	p.Name = unescapePURL(rest)
+	if p.Name == "" {
+		return packageURL{}, false
+	}
+
+	return p, true
+}
+
+// fullName returns the namespace and name joined with "/", e.g.
+// "@babel/core" for npm or "github.com/foo/bar" for golang.
+func (p packageURL) fullName() string {
+	if p.Namespace == "" {
+		return p.Name
+	}
+
+	return p.Namespace + "/" + p.Name
+}
+
+// base returns the decoded PURL without version, qualifiers and subpath, e.g.
+// "pkg:npm/@babel/core".
+func (p packageURL) base() string {
+	return "pkg:" + p.Type + "/" + p.fullName()
+}
+
+// matchingPURLKeys returns the keys of entries that match the PURL p, from the
+// most specific one.
+//
+// Keys match the decoded PURL without version, qualifiers and subpath, either
+// exactly or as a path prefix: "pkg:golang/github.com/foo/bar" matches
+// sub-packages like "pkg:golang/github.com/foo/bar/v2/sub@v2.1.0". A key with a
+// version only matches that version. Keys with a version come first, then the
+// longest keys.
+func matchingPURLKeys[V any](p packageURL, entries map[string]V) []string {
+	base := p.base()
+
+	type candidate struct {
+		key   string
+		rank  int
+		exact bool
+	}
+
+	var candidates []candidate
+
+	for key := range entries {
+		k, ok := parsePURL(key)
+		if !ok {
+			continue
+		}
+
+		switch {
+		case k.Version != "":
+			if k.base() == base && k.Version == p.Version {
+				candidates = append(candidates, candidate{key: key, rank: len(k.base()), exact: true})
+			}
+		case base == k.base() || strings.HasPrefix(base, k.base()+"/"):
+			candidates = append(candidates, candidate{key: key, rank: len(k.base())})
+		}
+	}
+
+	sort.Slice(candidates, func(i, j int) bool {
+		if candidates[i].exact != candidates[j].exact {
+			return candidates[i].exact
+		}
+
+		if candidates[i].rank != candidates[j].rank {
+			return candidates[i].rank > candidates[j].rank
+		}
+
+		return candidates[i].key < candidates[j].key
+	})
+
+	keys := make([]string, len(candidates))
+	for i, c := range candidates {
+		keys[i] = c.key
+	}
+
+	return keys
+}
+
+func parsePURLQualifiers(raw string) map[string]string {
+	qualifiers := map[string]string{}
+
+	for pair := range strings.SplitSeq(raw, "&") {
+		key, value, _ := strings.Cut(pair, "=")
+
+		key = strings.ToLower(strings.TrimSpace(key))
+		value = unescapePURL(value)
+
+		// Qualifiers with an empty value are discarded.
+		if key != "" && value != "" {
+			qualifiers[key] = value
+		}
+	}
+
+	if len(qualifiers) == 0 {
+		return nil
+	}
+
+	return qualifiers
+}
+
+// decodePURLSegments decodes the "/"-separated segments of a namespace or
+// subpath, discarding empty segments and, for subpaths, "." and "..".
+func decodePURLSegments(raw string, subpath bool) string {
+	var segments []string
+
+	for segment := range strings.SplitSeq(raw, "/") {
+		segment = unescapePURL(segment)
+		if segment == "" || subpath && (segment == "." || segment == "..") {
+			continue
+		}
+
+		segments = append(segments, segment)
+	}
+
+	return strings.Join(segments, "/")
+}
+
+func unescapePURL(s string) string {
+	if u, err := url.PathUnescape(s); err == nil {
+		return u
+	}
+
+	return s
+}
 
 func shouldIgnorePURL(filters Filters, purl string) bool {
 	if purl == "" {
 		return false
 	}
 
+	// Match the decoded form as well, so that "pkg:npm/%40scope/name" and
+	// "pkg:npm/@scope/name" are filtered by the same pattern.
+	candidates := []string{purl}
+	if p, ok := parsePURL(purl); ok {
+		candidates = append(candidates, p.base()+"@"+p.Version)
+	}
+
 	for _, re := range filters.PURLRegex {
-		if re.MatchString(purl) {
-			return true
+		for _, candidate := range candidates {
+			if re.MatchString(candidate) {
+				return true
+			}
 		}
 	}
 
 	return false
 }
 
+// goVanityRepos maps Go vanity import paths to their source repository. When
+// prefix ends with "/", the next path element names a repository of the same
+// name under repo.
+var goVanityRepos = []struct {
+	prefix string
+	repo   string
+}{
+	{prefix: "cloud.google.com/go", repo: "https://github.com/googleapis/google-cloud-go"},
+	{prefix: "go.etcd.io/", repo: "https://github.com/etcd-io/"},
+	{prefix: "go.opentelemetry.io/contrib", repo: "https://github.com/open-telemetry/opentelemetry-go-contrib"},
+	{prefix: "go.opentelemetry.io/otel", repo: "https://github.com/open-telemetry/opentelemetry-go"},
+	{prefix: "go.opentelemetry.io/proto/otlp", repo: "https://github.com/open-telemetry/opentelemetry-proto-go"},
+	{prefix: "go.uber.org/", repo: "https://github.com/uber-go/"},
+	{prefix: "golang.org/x/", repo: "https://github.com/golang/"},
+	{prefix: "google.golang.org/api", repo: "https://github.com/googleapis/google-api-go-client"},
+	{prefix: "google.golang.org/genproto", repo: "https://github.com/googleapis/go-genproto"},
+	{prefix: "google.golang.org/grpc", repo: "https://github.com/grpc/grpc-go"},
+	{prefix: "google.golang.org/protobuf", repo: "https://github.com/protocolbuffers/protobuf-go"},
+	{prefix: "k8s.io/", repo: "https://github.com/kubernetes/"},
+	{prefix: "sigs.k8s.io/", repo: "https://github.com/kubernetes-sigs/"},
+}
+
+// goModuleURL returns the source repository of a Go module path, falling back
+// to its pkg.go.dev page.
+func goModuleURL(path string) string {
+	parts := strings.Split(path, "/")
+
+	switch parts[0] {
+	case "github.com", "gitlab.com", "bitbucket.org":
+		if len(parts) >= 3 {
+			return "https://" + strings.Join(parts[:3], "/")
+		}
+	case "gopkg.in":
+		// gopkg.in/pkg.v3 is github.com/go-pkg/pkg, gopkg.in/user/pkg.v3 is
+		// github.com/user/pkg.
+		if len(parts) >= 2 {
+			if name, _, ok := strings.Cut(parts[1], ".v"); ok {
+				return "https://github.com/go-" + name + "/" + name
+			}
+		}
+
+		if len(parts) >= 3 {
+			if name, _, ok := strings.Cut(parts[2], ".v"); ok {
+				return "https://github.com/" + parts[1] + "/" + name
+			}
+		}
+	}
+
+	for _, v := range goVanityRepos {
+		if strings.HasSuffix(v.prefix, "/") {
+			if rest, ok := strings.CutPrefix(path, v.prefix); ok && rest != "" {
+				repo, _, _ := strings.Cut(rest, "/")
+
+				return v.repo + repo
+			}
+
+			continue
+		}
+
+		if path == v.prefix || strings.HasPrefix(path, v.prefix+"/") {
+			return v.repo
+		}
+	}
+
+	return "https://pkg.go.dev/" + path
+}
+
+// componentURLFromPurl derives the upstream page of a component from its
+// PURL: the package registry page, or the source repository when the PURL
+// identifies one.
 func componentURLFromPurl(purl string) string {
-	m := purlRegex.FindStringSubmatch(purl)
-	if len(m) != 4 {
+	p, ok := parsePURL(purl)
+	if !ok {
 		return ""
 	}
 
-	typ := m[1]
-	name := m[2]
-
-	switch typ {
+	switch p.Type {
 	case "npm":
-		return "https://www.npmjs.com/package/" + name
+		return "https://www.npmjs.com/package/" + p.fullName()
 	case "pypi":
-		return "https://pypi.org/project/" + name + "/"
+		return "https://pypi.org/project/" + p.Name + "/"
+	case "cargo":
+		return "https://crates.io/crates/" + p.Name
+	case "maven":
+		if p.Namespace == "" {
+			return ""
+		}
+
+		return "https://central.sonatype.com/artifact/" + p.fullName()
+	case "composer":
+		return "https://packagist.org/packages/" + p.fullName()
+	case "gem":
+		return "https://rubygems.org/gems/" + p.Name
+	case "nuget":
+		return "https://www.nuget.org/packages/" + p.Name
 	case "golang":
-		if strings.HasPrefix(name, "github.com/") {
-			parts := strings.Split(name, "/")
-			if len(parts) >= 3 {
-				return "https://github.com/" + parts[1] + "/" + parts[2]
-			}
+		return goModuleURL(p.fullName())
+	case "github", "gitlab", "bitbucket":
+		if p.Namespace == "" {
+			return ""
 		}
 
-		return "https://pkg.go.dev/" + name
+		host := map[string]string{"github": "github.com", "gitlab": "gitlab.com", "bitbucket": "bitbucket.org"}[p.Type]
+
+		return "https://" + host + "/" + p.fullName()
+	case "deb":
+		return debianPackageURL(p)
+	case "apk":
+		if !strings.EqualFold(p.Namespace, "alpine") {
+			return ""
+		}
+
+		return "https://pkgs.alpinelinux.org/packages?name=" + url.QueryEscape(p.Name)
+	default:
+		return ""
+	}
+}
+
+// debianPackageURL returns the tracker page of the source package of a deb
+// PURL. The "upstream" qualifier, when present, names the source package.
+func debianPackageURL(p packageURL) string {
+	source := p.Name
+	if upstream := p.Qualifiers["upstream"]; upstream != "" {
+		// The qualifier may carry the source version: "openssl@3.0.11-1".
+		source, _, _ = strings.Cut(upstream, "@")
+	}
+
+	switch strings.ToLower(p.Namespace) {
+	case "debian":
+		return "https://tracker.debian.org/pkg/" + url.PathEscape(source)
+	case "ubuntu":
+		return "https://launchpad.net/ubuntu/+source/" + url.PathEscape(source)
 	default:
 		return ""
 	}
@@ -18,6 +18,78 @@ func TestShouldIgnorePURL(t *testing.T) {
 	assert.True(t, shouldIgnorePURL(filters, "pkg:golang/use.local/bar@v1.0.0"))
 }
 
+func TestShouldIgnorePURL_Decoded(t *testing.T) {
+	t.Parallel()
+
+	filters := Filters{}
+	filters.PURLRegex = []*regexp.Regexp{regexp.MustCompile(`^pkg:npm/@traefik/`)}
+
+	assert.True(t, shouldIgnorePURL(filters, "pkg:npm/@traefik/ui@1.0.0"))
+	assert.True(t, shouldIgnorePURL(filters, "pkg:npm/%40traefik/ui@1.0.0"))
+	assert.False(t, shouldIgnorePURL(filters, "pkg:npm/traefik@1.0.0"))
+}
+
+func TestParsePURL(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		purl string
+		want packageURL
+	}{
+		{
+			purl: "pkg:npm/%40babel/core@7.24.0",
+			want: packageURL{Type: "npm", Namespace: "@babel", Name: "core", Version: "7.24.0"},
+		},
+		{
+			purl: "pkg:npm/@babel/core@7.24.0",
+			want: packageURL{Type: "npm", Namespace: "@babel", Name: "core", Version: "7.24.0"},
+		},
+		{
+			purl: "pkg:npm/@babel/core",
+			want: packageURL{Type: "npm", Namespace: "@babel", Name: "core"},
+		},
+		{
+			purl: "pkg:golang/github.com/BurntSushi/toml@v1.5.0?type=module",
+			want: packageURL{Type: "golang", Namespace: "github.com/BurntSushi", Name: "toml", Version: "v1.5.0", Qualifiers: map[string]string{"type": "module"}},
+		},
+		{
+			purl: "pkg:golang/std@go1.25.3",
+			want: packageURL{Type: "golang", Name: "std", Version: "go1.25.3"},
+		},
+		{
+			purl: "pkg:deb/debian/curl@7.88.1-10%2Bdeb12u5?arch=amd64&distro=debian-12&empty=",
+			want: packageURL{Type: "deb", Namespace: "debian", Name: "curl", Version: "7.88.1-10+deb12u5", Qualifiers: map[string]string{"arch": "amd64", "distro": "debian-12"}},
+		},
+		{
+			purl: "PKG:Maven/org.apache.commons/commons-lang3@3.14.0#src/main/../java/",
+			want: packageURL{Type: "maven", Namespace: "org.apache.commons", Name: "commons-lang3", Version: "3.14.0", Subpath: "src/main/java"},
+		},
+		{
+			purl: "pkg://github/traefik/traefik@v3.6.0",
+			want: packageURL{Type: "github", Namespace: "traefik", Name: "traefik", Version: "v3.6.0"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.purl, func(t *testing.T) {
+			t.Parallel()
+
+			got, ok := parsePURL(test.purl)
+			assert.True(t, ok)
+			assert.Equal(t, test.want, got)
+		})
+	}
+}
+
+func TestParsePURL_Invalid(t *testing.T) {
+	t.Parallel()
+
+	for _, purl := range []string{"", "not-a-purl", "npm/foo@1.0.0", "pkg:npm", "pkg:npm/@1.0.0"} {
+		_, ok := parsePURL(purl)
+		assert.False(t, ok, purl)
+	}
+}
+
 func TestComponentURLFromPurl(t *testing.T) {
 	t.Parallel()
 
@@ -27,6 +99,44 @@ func TestComponentURLFromPurl(t *testing.T) {
 	assert.Equal(t, "https://pypi.org/project/bar/", componentURLFromPurl("pkg:pypi/bar@2.3.4"))
 
 	assert.Equal(t, "https://github.com/traefik/traefik", componentURLFromPurl("pkg:golang/github.com/traefik/traefik@v3.6.0"))
-	assert.Equal(t, "https://pkg.go.dev/golang.org/x/baz", componentURLFromPurl("pkg:golang/golang.org/x/baz@v4.5.6"))
-	assert.Empty(t, componentURLFromPurl("pkg:maven/com.example/qux@1.0.0"))
+	assert.Equal(t, "https://pkg.go.dev/example.org/baz", componentURLFromPurl("pkg:golang/example.org/baz@v4.5.6"))
+	assert.Equal(t, "https://crates.io/crates/serde", componentURLFromPurl("pkg:cargo/serde@1.0.197"))
+	assert.Equal(t, "https://central.sonatype.com/artifact/com.example/qux", componentURLFromPurl("pkg:maven/com.example/qux@1.0.0"))
+	assert.Equal(t, "https://packagist.org/packages/symfony/console", componentURLFromPurl("pkg:composer/symfony/console@v6.4.4"))
+	assert.Equal(t, "https://rubygems.org/gems/nokogiri", componentURLFromPurl("pkg:gem/nokogiri@1.16.2?platform=x86_64-linux"))
+	assert.Equal(t, "https://www.nuget.org/packages/Newtonsoft.Json", componentURLFromPurl("pkg:nuget/Newtonsoft.Json@13.0.3"))
+	assert.Equal(t, "https://www.npmjs.com/package/@babel/core", componentURLFromPurl("pkg:npm/%40babel/core@7.24.0"))
+	assert.Equal(t, "https://github.com/traefik/traefik", componentURLFromPurl("pkg:github/traefik/traefik@v3.6.0"))
+	assert.Equal(t, "https://gitlab.com/gitlab-org/gitlab-runner", componentURLFromPurl("pkg:gitlab/gitlab-org/gitlab-runner@v16.0.0"))
+	assert.Equal(t, "https://bitbucket.org/atlassian/python-bitbucket", componentURLFromPurl("pkg:bitbucket/atlassian/python-bitbucket@1.0"))
+	assert.Equal(t, "https://tracker.debian.org/pkg/curl", componentURLFromPurl("pkg:deb/debian/libcurl4@7.88.1-10?arch=amd64&upstream=curl"))
+	assert.Equal(t, "https://launchpad.net/ubuntu/+source/zlib1g", componentURLFromPurl("pkg:deb/ubuntu/zlib1g@1:1.2.13"))
+	assert.Equal(t, "https://pkgs.alpinelinux.org/packages?name=busybox", componentURLFromPurl("pkg:apk/alpine/busybox@1.36.1-r15?arch=x86_64"))
+	assert.Empty(t, componentURLFromPurl("pkg:rpm/redhat/openssl-libs@3.0.7-27.el9"))
+	assert.Empty(t, componentURLFromPurl("pkg:generic/foo@1.0.0"))
+}
+
+func TestComponentURLFromPurl_GoVanity(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]string{
+		"pkg:golang/golang.org/x/net@v0.20.0":                       "https://github.com/golang/net",
+		"pkg:golang/golang.org/x/text/encoding@v0.14.0":             "https://github.com/golang/text",
+		"pkg:golang/k8s.io/client-go@v0.29.0":                       "https://github.com/kubernetes/client-go",
+		"pkg:golang/sigs.k8s.io/yaml@v1.4.0":                        "https://github.com/kubernetes-sigs/yaml",
+		"pkg:golang/gopkg.in/yaml.v3@v3.0.1":                        "https://github.com/go-yaml/yaml",
+		"pkg:golang/gopkg.in/DataDog/dd-trace-go.v1@v1.60.0":        "https://github.com/DataDog/dd-trace-go",
+		"pkg:golang/go.uber.org/zap@v1.26.0":                        "https://github.com/uber-go/zap",
+		"pkg:golang/google.golang.org/grpc@v1.60.0":                 "https://github.com/grpc/grpc-go",
+		"pkg:golang/google.golang.org/protobuf@v1.32.0":             "https://github.com/protocolbuffers/protobuf-go",
+		"pkg:golang/go.opentelemetry.io/otel/sdk@v1.21.0":           "https://github.com/open-telemetry/opentelemetry-go",
+		"pkg:golang/cloud.google.com/go/storage@v1.36.0":            "https://github.com/googleapis/google-cloud-go",
+		"pkg:golang/gitlab.com/gitlab-org/api/client-go@v0.1.0":     "https://gitlab.com/gitlab-org/api",
+		"pkg:golang/github.com/nrdcg/oci-go-sdk/v65/common@v65.0.0": "https://github.com/nrdcg/oci-go-sdk",
+		"pkg:golang/std@go1.25.3":                                   "https://pkg.go.dev/std",
+	}
+
+	for purl, want := range tests {
+		assert.Equal(t, want, componentURLFromPurl(purl), purl)
+	}
 }
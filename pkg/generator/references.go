@@ -0,0 +1,191 @@
+package generator
+
+import (
+	"net/url"
+	"slices"
+	"strings"
+)
+
+// externalReferenceTypes lists the CycloneDX external reference types used as
+// the upstream link of a component, in order of preference.
+var externalReferenceTypes = []string{"vcs", "website"}
+
+// componentURL returns the upstream link of a component: its vcs and website
+// external references first, then the link derived from its PURL, then its
+// distribution reference, often a download URL of the package archive, then
+// the URL of its manufacturer or supplier.
+func componentURL(c Component) string {
+	if u := externalReferenceURL(c, externalReferenceTypes...); u != "" {
+		return u
+	}
+
+	if u := componentURLFromPurl(c.PURL); u != "" {
+		return u
+	}
+
+	if u := externalReferenceURL(c, "distribution"); u != "" {
+		return u
+	}
+
+	for _, entity := range []*OrganizationalEntity{c.Manufacturer, c.Supplier} {
+		if entity == nil {
+			continue
+		}
+
+		for _, raw := range entity.URL {
+			if u := normalizeReferenceURL(raw); u != "" {
+				return u
+			}
+		}
+	}
+
+	return ""
+}
+
+// externalReferenceURL returns the first usable external reference of the
+// component with one of the given types, in their order.
+func externalReferenceURL(c Component, types ...string) string {
+	for _, typ := range types {
+		for _, ref := range c.ExternalReferences {
+			if !strings.EqualFold(ref.Type, typ) {
+				continue
+			}
+
+			if u := normalizeReferenceURL(ref.URL); u != "" {
+				return u
+			}
+		}
+	}
+
+	return ""
+}
+
+// componentDocumentationURL returns the documentation link of a component: its
+// "documentation" external reference, or the docs.rs page of a crate from
+// crates.io, which builds the documentation of every published crate.
+func componentDocumentationURL(c Component) string {
+	if u := externalReferenceURL(c, "documentation"); u != "" {
+		return u
+	}
+
+	p, ok := parsePURL(c.PURL)
+	if !ok || p.Type != "cargo" {
+		return ""
+	}
+
+	// docs.rs only covers crates.io, the default registry.
+	if registry := p.Qualifiers["repository_url"]; registry != "" && !strings.Contains(registry, "crates.io") {
+		return ""
+	}
+
+	u := "https://docs.rs/" + p.Name
+	if p.Version != "" {
+		u += "/" + p.Version
+	}
+
+	return u
+}
+
+// normalizeReferenceURL turns a reference into a browsable https URL, or
+// returns an empty string. VCS references are commonly written as
+// "git+https://host/repo.git", "git://host/repo" or "git@host:repo.git".
+func normalizeReferenceURL(raw string) string {
+	raw = strings.TrimSpace(raw)
+
+	if rest, ok := strings.CutPrefix(raw, "git@"); ok {
+		if host, path, ok := strings.Cut(rest, ":"); ok {
+			raw = "https://" + host + "/" + path
+		}
+	}
+
+	raw = strings.TrimPrefix(raw, "git+")
+	raw = strings.TrimPrefix(raw, "scm:git:")
+
+	u, err := url.Parse(raw)
+	if err != nil || u.Host == "" {
+		return ""
+	}
+
+	switch u.Scheme {
+	case "http", "https":
+	case "git", "ssh":
+		u.Scheme = "https"
+		u.User = nil
+		u.Host = u.Hostname()
+	default:
+		return ""
+	}
+
+	if isRepositoryHost(u.Host) {
+		u.Path = strings.TrimSuffix(strings.TrimSuffix(u.Path, "/"), ".git")
+		u.Fragment = ""
+	}
+
+	return u.String()
+}
+
+func isRepositoryHost(host string) bool {
+	switch strings.ToLower(host) {
+	case "github.com", "gitlab.com", "bitbucket.org", "codeberg.org":
+		return true
+	default:
+		return false
+	}
+}
+
+// sbomPartyNotice builds a copyright notice from the parties the SBOM names for
+// a component: its authors, then its publisher, then its manufacturer. It is
+// the last resort, used when neither the SBOM nor the package files carry a
+// copyright. The supplier is not used: it distributes the component but is
+// often not its copyright holder (e.g. a Linux distribution).
+func sbomPartyNotice(c Component) notice {
+	var authors []string
+
+	for _, a := range c.Authors {
+		if name := strings.TrimSpace(a.Name); name != "" {
+			authors = append(authors, name)
+		}
+	}
+
+	if len(authors) > 0 {
+		return notice{Text: "Copyright (c) " + strings.Join(authors, ", "), Source: "sbom authors"}
+	}
+
+	if name := personName(c.Author); name != "" {
+		return notice{Text: "Copyright (c) " + name, Source: "sbom author"}
+	}
+
+	if name := strings.TrimSpace(c.Publisher); name != "" {
+		return notice{Text: "Copyright (c) " + name, Source: "sbom publisher"}
+	}
+
+	if c.Manufacturer != nil {
+		if name := strings.TrimSpace(c.Manufacturer.Name); name != "" {
+			return notice{Text: "Copyright (c) " + name, Source: "sbom manufacturer"}
+		}
+	}
+
+	return notice{}
+}
+
+// sbomEvidenceNotice builds a copyright notice from the copyright statements
+// the SBOM generator found in the component files.
+func sbomEvidenceNotice(c Component) notice {
+	if c.Evidence == nil {
+		return notice{}
+	}
+
+	var lines []string
+
+	for _, cp := range c.Evidence.Copyright {
+		if text := strings.TrimSpace(cp.Text); text != "" && !slices.Contains(lines, text) {
+			lines = append(lines, text)
+		}
+	}
+
+	if len(lines) == 0 {
+		return notice{}
+	}
+
+	return notice{Text: strings.Join(lines, "\n"), Source: "sbom evidence"}
+}
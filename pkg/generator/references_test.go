@@ -0,0 +1,134 @@
+package generator
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestComponentURL_ExternalReferences(t *testing.T) {
+	t.Parallel()
+
+	c := Component{
+		PURL: "pkg:npm/foo@1.0.0",
+		ExternalReferences: []ExternalReference{
+			{Type: "distribution", URL: "https://registry.npmjs.org/foo/-/foo-1.0.0.tgz"},
+			{Type: "website", URL: "https://foo.dev"},
+			{Type: "vcs", URL: "git+https://github.com/acme/foo.git"},
+		},
+	}
+
+	assert.Equal(t, "https://github.com/acme/foo", componentURL(c))
+
+	c.ExternalReferences = c.ExternalReferences[:2]
+	assert.Equal(t, "https://foo.dev", componentURL(c))
+
+	// The registry page is preferred to a download URL.
+	c.ExternalReferences = c.ExternalReferences[:1]
+	assert.Equal(t, "https://www.npmjs.com/package/foo", componentURL(c))
+
+	c.PURL = ""
+	assert.Equal(t, "https://registry.npmjs.org/foo/-/foo-1.0.0.tgz", componentURL(c))
+}
+
+func TestComponentURL_Fallbacks(t *testing.T) {
+	t.Parallel()
+
+	// Unusable references fall back to the PURL.
+	c := Component{
+		PURL:               "pkg:npm/foo@1.0.0",
+		ExternalReferences: []ExternalReference{{Type: "vcs", URL: "svn+ssh://svn.example.com/foo"}},
+	}
+	assert.Equal(t, "https://www.npmjs.com/package/foo", componentURL(c))
+
+	// Components without a PURL use their manufacturer or supplier.
+	c = Component{
+		Name:     "foo",
+		Supplier: &OrganizationalEntity{Name: "Acme", URL: []string{"https://acme.example.com"}},
+	}
+	assert.Equal(t, "https://acme.example.com", componentURL(c))
+
+	assert.Empty(t, componentURL(Component{Name: "foo"}))
+}
+
+func TestComponentDocumentationURL(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "https://docs.rs/serde/1.0.197", componentDocumentationURL(Component{PURL: "pkg:cargo/serde@1.0.197"}))
+	assert.Equal(t, "https://docs.rs/serde", componentDocumentationURL(Component{PURL: "pkg:cargo/serde"}))
+	assert.Empty(t, componentDocumentationURL(Component{PURL: "pkg:cargo/internal@1.0.0?repository_url=https://cargo.example.com/index"}))
+	assert.Empty(t, componentDocumentationURL(Component{PURL: "pkg:npm/foo@1.0.0"}))
+
+	c := Component{
+		PURL:               "pkg:cargo/tokio@1.36.0",
+		ExternalReferences: []ExternalReference{{Type: "documentation", URL: "https://tokio.rs/tokio/tutorial"}},
+	}
+	assert.Equal(t, "https://tokio.rs/tokio/tutorial", componentDocumentationURL(c))
+	assert.Equal(t, "https://crates.io/crates/tokio", componentURL(c))
+}
+
+func TestNormalizeReferenceURL(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]string{
+		"https://github.com/acme/foo":             "https://github.com/acme/foo",
+		"git+https://github.com/acme/foo.git":     "https://github.com/acme/foo",
+		"git://github.com/acme/foo.git":           "https://github.com/acme/foo",
+		"git@github.com:acme/foo.git":             "https://github.com/acme/foo",
+		"ssh://git@gitlab.com/acme/foo.git":       "https://gitlab.com/acme/foo",
+		"scm:git:https://github.com/acme/foo.git": "https://github.com/acme/foo",
+		"https://foo.dev/docs/":                   "https://foo.dev/docs/",
+		"http://example.com/foo.git":              "http://example.com/foo.git",
+		"svn+ssh://svn.example.com/foo":           "",
+		"not a url":                               "",
+		"":                                        "",
+	}
+
+	for raw, want := range tests {
+		assert.Equal(t, want, normalizeReferenceURL(raw), raw)
+	}
+}
+
+func TestSbomPartyNotice(t *testing.T) {
+	t.Parallel()
+
+	c := Component{
+		Authors:      []OrganizationalContact{{Name: "Jane Doe", Email: "jane@example.com"}, {Name: "John Doe"}},
+		Author:       "Someone Else",
+		Publisher:    "Acme Publishing",
+		Manufacturer: &OrganizationalEntity{Name: "Acme Corp"},
+	}
+	assert.Equal(t, notice{Text: "Copyright (c) Jane Doe, John Doe", Source: "sbom authors"}, sbomPartyNotice(c))
+
+	c.Authors = nil
+	assert.Equal(t, notice{Text: "Copyright (c) Someone Else", Source: "sbom author"}, sbomPartyNotice(c))
+
+	c.Author = ""
+	assert.Equal(t, notice{Text: "Copyright (c) Acme Publishing", Source: "sbom publisher"}, sbomPartyNotice(c))
+
+	c.Publisher = ""
+	assert.Equal(t, notice{Text: "Copyright (c) Acme Corp", Source: "sbom manufacturer"}, sbomPartyNotice(c))
+
+	c.Manufacturer = nil
+	c.Supplier = &OrganizationalEntity{Name: "Debian"}
+	assert.Empty(t, sbomPartyNotice(c))
+}
+
+func TestBuildIndex_SBOMParties(t *testing.T) {
+	t.Parallel()
+
+	components := []Component{{
+		Name:               "foo",
+		Version:            "1.0.0",
+		Author:             "Jane Doe <jane@example.com>",
+		ExternalReferences: []ExternalReference{{Type: "website", URL: "https://foo.dev"}},
+		Licenses:           []LicenseChoice{{Expression: "MIT"}},
+	}}
+
+	_, byKey := buildIndex(components, inputs{}, copyrightEnricher{}, nil)
+
+	got := byKey["foo@1.0.0"]
+	assert.Equal(t, "https://foo.dev", got.URL)
+	assert.Equal(t, "Copyright (c) Jane Doe", got.Copyright)
+	assert.Equal(t, "sbom author", got.CopyrightSource)
+}
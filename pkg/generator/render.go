@@ -5,53 +5,124 @@ import (
 	"embed"
 	"fmt"
 	"html/template"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
 	texttpl "text/template"
+	"text/template/parse"
 )
 
-func renderHTML(cfg Config, embedded embed.FS, model any) (string, error) {
-	var tpl *template.Template
+// Embedded templates of the HTML and NOTICE files, relative to the templates
+// directory.
+const (
+	embeddedHTMLTemplate   = "third_party_licenses.gotpl"
+	embeddedNoticeTemplate = "notice.gotpl"
+)
+
+// templateSet is implemented by the templates of html/template and
+// text/template.
+type templateSet[T any] interface {
+	New(name string) T
+	Parse(text string) (T, error)
+	ParseFS(fsys fs.FS, patterns ...string) (T, error)
+	ParseGlob(pattern string) (T, error)
+	ExecuteTemplate(wr io.Writer, name string, data any) error
+}
 
-	var err error
+// renderOutput renders the template of the output with the model. A custom
+// template of the output extends the embedded template of its engine.
+func renderOutput(o Output, templateDir string, embedded embed.FS, model any) (string, error) {
+	engine := o.engine()
 
-	if cfg.HTMLTemplatePath != "" {
-		tpl, err = template.ParseFiles(cfg.HTMLTemplatePath)
-	} else {
-		tpl, err = template.ParseFS(embedded, "templates/third_party_licenses.gotpl")
+	name, path := embeddedNoticeTemplate, o.Template
+	if engine == engineHTML {
+		name = embeddedHTMLTemplate
 	}
 
-	if err != nil {
-		return "", fmt.Errorf("failed to parse HTML template: %w", err)
+	if builtin, ok := builtinTemplates[o.Template]; ok {
+		name, path = builtin.file, ""
 	}
 
-	var buf bytes.Buffer
+	var (
+		out string
+		err error
+	)
 
-	if err := tpl.Execute(&buf, model); err != nil {
-		return "", fmt.Errorf("failed to execute HTML template: %w", err)
+	switch engine {
+	case engineHTML:
+		out, err = renderTemplate(template.New("").Funcs(templateFuncs()), embedded, name, path, templateDir, model)
+	default:
+		out, err = renderTemplate(texttpl.New("").Funcs(templateFuncs()), embedded, name, path, templateDir, model)
 	}
 
-	return buf.String(), nil
-}
+	if err != nil {
+		return "", fmt.Errorf("template %q: %w", o.Template, err)
+	}
 
-func renderText(cfg Config, embedded embed.FS, model any) (string, error) {
-	var tpl *texttpl.Template
+	return out, nil
+}
 
-	var err error
+// renderTemplate renders the embedded template name, or the template at path
+// when set. The partial templates of dir are parsed first, so that templates
+// can include them with {{template "name" .}}.
+//
+// The embedded template is always parsed: a template at path that only
+// defines templates extends it, overriding its blocks.
+func renderTemplate[T templateSet[T]](tpl T, embedded embed.FS, name, path, dir string, model any) (string, error) {
+	tpl, err := tpl.ParseFS(embedded, "templates/"+name)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse embedded template: %w", err)
+	}
 
-	if cfg.NoticeTplPath != "" {
-		tpl, err = texttpl.ParseFiles(cfg.NoticeTplPath)
-	} else {
-		tpl, err = texttpl.ParseFS(embedded, "templates/notice.gotpl")
+	if dir != "" {
+		tpl, err = tpl.ParseGlob(filepath.Join(dir, "*.gotpl"))
+		if err != nil {
+			return "", fmt.Errorf("failed to parse template directory: %w", err)
+		}
 	}
 
-	if err != nil {
-		return "", fmt.Errorf("failed to parse notice template: %w", err)
+	if path != "" {
+		text, err := os.ReadFile(path)
+		if err != nil {
+			return "", fmt.Errorf("failed to read template: %w", err)
+		}
+
+		extends, err := definesOnly(filepath.Base(path), string(text))
+		if err != nil {
+			return "", fmt.Errorf("failed to parse template: %w", err)
+		}
+
+		if _, err := tpl.New(filepath.Base(path)).Parse(string(text)); err != nil {
+			return "", fmt.Errorf("failed to parse template: %w", err)
+		}
+
+		if !extends {
+			name = filepath.Base(path)
+		}
 	}
 
 	var buf bytes.Buffer
 
-	if err := tpl.Execute(&buf, model); err != nil {
-		return "", fmt.Errorf("failed to execute notice template: %w", err)
+	if err := tpl.ExecuteTemplate(&buf, name, model); err != nil {
+		return "", fmt.Errorf("failed to execute template: %w", err)
 	}
 
 	return buf.String(), nil
 }
+
+// definesOnly reports whether the template text only defines templates, such
+// as {{define "html-component"}}, with nothing else than spaces around them.
+func definesOnly(name, text string) (bool, error) {
+	t := parse.New(name)
+	t.Mode = parse.SkipFuncCheck
+
+	trees := map[string]*parse.Tree{}
+	if _, err := t.Parse(text, "", "", trees); err != nil {
+		return false, err
+	}
+
+	root, ok := trees[name]
+
+	return !ok || parse.IsEmptyTree(root.Root), nil
+}
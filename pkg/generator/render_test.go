@@ -1,6 +1,11 @@
 package generator
 
 import (
+	"encoding/csv"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -10,10 +15,9 @@ import (
 func TestRenderText_EmbeddedTemplate(t *testing.T) {
 	t.Parallel()
 
-	cfg := Config{}
 	m := Model{GeneratedAt: "2026-01-01T00:00:00Z"}
 
-	out, err := renderText(cfg, embedded, m)
+	out, err := renderOutput(Output{Template: "notice", Path: "NOTICE.md"}, "", embedded, m)
 	require.NoError(t, err)
 	assert.Contains(t, out, "# NOTICE")
 	assert.Contains(t, out, "Generated at: 2026-01-01T00:00:00Z")
@@ -22,12 +26,306 @@ func TestRenderText_EmbeddedTemplate(t *testing.T) {
 func TestRenderHTML_EmbeddedTemplate(t *testing.T) {
 	t.Parallel()
 
-	cfg := Config{}
 	m := Model{GeneratedAt: "2026-01-01T00:00:00Z"}
 
-	out, err := renderHTML(cfg, embedded, m)
+	out, err := renderOutput(Output{Template: "html", Path: "THIRD_PARTY_LICENSES.html"}, "", embedded, m)
 	require.NoError(t, err)
 	assert.Contains(t, out, "Third Party Licenses")
 	assert.Contains(t, out, "Generated: 2026-01-01T00:00:00Z")
 	assert.Contains(t, out, "<!DOCTYPE html>")
 }
+
+func TestRenderText_Curation(t *testing.T) {
+	t.Parallel()
+
+	m := Model{Notices: []OutComponent{{
+		Name:            "bar",
+		Version:         "1.0.0",
+		Copyright:       "Copyright (c) Bar",
+		Notes:           []string{"Vendored in internal/bar."},
+		Modifications:   []string{"Modified by Traefik Labs."},
+		CopyrightSource: "LICENSE",
+	}}}
+
+	out, err := renderOutput(Output{Template: "notice", Path: "NOTICE.md"}, "", embedded, m)
+	require.NoError(t, err)
+	assert.Contains(t, out, "Modifications: Modified by Traefik Labs.")
+	assert.Contains(t, out, "Note: Vendored in internal/bar.")
+	assert.NotContains(t, out, "copyright source")
+
+	html, err := renderOutput(Output{Template: "html", Path: "THIRD_PARTY_LICENSES.html"}, "", embedded, Model{Licenses: []LicenseBlock{{ID: "MIT", UsedBy: m.Notices}}})
+	require.NoError(t, err)
+	assert.Contains(t, html, "Modifications: Modified by Traefik Labs.")
+	assert.Contains(t, html, "Vendored in internal/bar.")
+}
+
+func TestRenderOutput_DocumentationURL(t *testing.T) {
+	t.Parallel()
+
+	serde := OutComponent{Name: "serde", Version: "1.0.197", LicenseIDs: []string{"MIT"}, DocumentationURL: "https://docs.rs/serde/1.0.197"}
+	m := Model{
+		Licenses: []LicenseBlock{{ID: "MIT", Name: "MIT License", UsedBy: []OutComponent{serde}}},
+		Notices:  []OutComponent{serde},
+	}
+
+	testCases := map[string]string{
+		"markdown": "- serde 1.0.197 ([documentation](https://docs.rs/serde/1.0.197))",
+		"text":     "serde 1.0.197\nDocumentation: https://docs.rs/serde/1.0.197\nLicenses: MIT\n",
+		"html":     `<a href="https://docs.rs/serde/1.0.197">documentation</a>`,
+	}
+
+	for template, expected := range testCases {
+		out, err := renderOutput(Output{Template: template, Path: "out"}, "", embedded, m)
+		require.NoError(t, err)
+		assert.Contains(t, out, expected, template)
+	}
+}
+
+func TestRenderText_TemplateDirAndFuncs(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "component.gotpl"), []byte(`{{define "component"}}- {{.Name | escapeMarkdown}}: {{.LicenseIDs | join " AND "}}{{end}}`), 0o644))
+
+	tplPath := filepath.Join(t.TempDir(), "notice.txt.gotpl")
+	require.NoError(t, os.WriteFile(tplPath, []byte(`Generated on {{formatDate "2006-01-02" .GeneratedAt}}
+{{range groupByEcosystem .Notices}}## {{.Ecosystem | upper}}
+{{range .Components}}{{template "component" .}}
+{{end}}{{end}}`), 0o644))
+
+	m := Model{
+		GeneratedAt: "2026-01-01T10:00:00Z",
+		Notices: []OutComponent{
+			{Name: "left_pad", PURL: "pkg:npm/left_pad@1.0.0", LicenseIDs: []string{"MIT", "ISC"}},
+			{Name: "x", PURL: "pkg:golang/example.com/x@v1.0.0", LicenseIDs: []string{"BSD-3-Clause"}},
+		},
+	}
+
+	out, err := renderOutput(Output{Template: tplPath, Path: "NOTICE.txt"}, dir, embedded, m)
+	require.NoError(t, err)
+	assert.Equal(t, "Generated on 2026-01-01\n## GOLANG\n- x: BSD-3-Clause\n## NPM\n- left\\_pad: MIT AND ISC\n", out)
+}
+
+func TestRenderHTML_ExtendsEmbeddedTemplate(t *testing.T) {
+	t.Parallel()
+
+	tplPath := filepath.Join(t.TempDir(), "branded.gotpl")
+	require.NoError(t, os.WriteFile(tplPath, []byte(`
+{{/* Only override blocks of the embedded template. */}}
+{{define "html-title"}}Acme Licenses{{end}}
+{{define "html-component"}}<span id="{{.Name | slugify}}">{{.Name}}</span>{{end}}
+`), 0o644))
+
+	m := Model{Licenses: []LicenseBlock{{ID: "MIT", UsedBy: []OutComponent{{Name: "Left Pad", URL: "https://example.com"}}}}}
+
+	out, err := renderOutput(Output{Template: tplPath, Path: "licenses.html"}, "", embedded, m)
+	require.NoError(t, err)
+	assert.Contains(t, out, "<title>Acme Licenses</title>")
+	assert.Contains(t, out, `<span id="left-pad">Left Pad</span>`)
+	assert.Contains(t, out, "All license text")
+	assert.NotContains(t, out, "https://example.com")
+}
+
+func TestRenderHTML_InvalidTemplate(t *testing.T) {
+	t.Parallel()
+
+	tplPath := filepath.Join(t.TempDir(), "broken.gotpl")
+	require.NoError(t, os.WriteFile(tplPath, []byte(`{{.Name`), 0o644))
+
+	_, err := renderOutput(Output{Template: tplPath, Path: "licenses.html"}, "", embedded, Model{})
+	require.Error(t, err)
+
+	_, err = renderOutput(Output{Template: "html", Path: "licenses.html"}, t.TempDir(), embedded, Model{})
+	require.Error(t, err)
+}
+
+func TestRenderOutput_JSON(t *testing.T) {
+	t.Parallel()
+
+	m := Model{
+		GeneratedAt: "2026-01-01T00:00:00Z",
+		Notices:     []OutComponent{{Name: "foo", Version: "1.0.0", LicenseIDs: []string{"MIT"}, Copyright: "Copyright Foo"}},
+	}
+
+	out, err := renderOutput(Output{Template: "json", Path: "licenses.json"}, "", embedded, m)
+	require.NoError(t, err)
+
+	var back Model
+	require.NoError(t, json.Unmarshal([]byte(out), &back))
+	assert.Equal(t, m, back)
+	assert.Contains(t, out, `"generatedAt": "2026-01-01T00:00:00Z"`)
+}
+
+func TestRenderOutput_Engine(t *testing.T) {
+	t.Parallel()
+
+	tplPath := filepath.Join(t.TempDir(), "page.gotpl")
+	require.NoError(t, os.WriteFile(tplPath, []byte(`{{range .Notices}}{{.Copyright}}{{end}}`), 0o644))
+
+	m := Model{Notices: []OutComponent{{Name: "foo", Copyright: "Copyright <Foo>"}}}
+
+	out, err := renderOutput(Output{Template: tplPath, Path: "page.html"}, "", embedded, m)
+	require.NoError(t, err)
+	assert.Equal(t, "Copyright &lt;Foo&gt;", out)
+
+	out, err = renderOutput(Output{Template: tplPath, Path: "page.html", Engine: engineText}, "", embedded, m)
+	require.NoError(t, err)
+	assert.Equal(t, "Copyright <Foo>", out)
+}
+
+func TestRenderOutput_Presets(t *testing.T) {
+	t.Parallel()
+
+	m := Model{
+		RepoName:    "traefik",
+		GeneratedAt: "2026-01-01T00:00:00Z",
+		Overview:    []OverviewItem{{ID: "MIT", Name: "MIT License", Anchor: "MIT", Count: 1}},
+		Licenses: []LicenseBlock{{
+			ID:     "MIT",
+			Name:   "MIT License",
+			Anchor: "MIT",
+			Text:   "Permission is hereby granted, free of charge, to any person obtaining a copy of this software.",
+			UsedBy: []OutComponent{{Name: "left_pad", Version: "1.0.0", URL: "https://example.com/left_pad", Copyright: "Copyright (c) 2014 Foo"}},
+		}},
+		Notices: []OutComponent{{
+			Name:       "left_pad",
+			Version:    "1.0.0",
+			URL:        "https://example.com/left_pad",
+			LicenseIDs: []string{"MIT"},
+			Copyright:  "Copyright (c) 2014 Foo",
+		}},
+	}
+
+	testCases := []struct {
+		template string
+		wrapped  bool
+		expected []string
+	}{
+		{
+			template: "markdown",
+			expected: []string{"- [MIT License](#mit) (1)", `<a id="mit"></a>`, "- [left\\_pad 1.0.0](https://example.com/left_pad)"},
+		},
+		{
+			template: "text",
+			wrapped:  true,
+			expected: []string{"left_pad 1.0.0\nUpstream: https://example.com/left_pad\nLicenses: MIT\n", "Copyright (c) 2014 Foo"},
+		},
+		{
+			template: "apache-notice",
+			wrapped:  true,
+			expected: []string{"traefik\n=======\n", "This product includes left_pad 1.0.0 (https://example.com/left_pad), licensed\nunder MIT."},
+		},
+		{
+			template: "all-in-one",
+			wrapped:  true,
+			expected: []string{"  - left_pad 1.0.0 <https://example.com/left_pad>\n    Copyright (c) 2014 Foo", "Permission is hereby granted, free of charge, to any person obtaining a copy of\nthis software."},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.template, func(t *testing.T) {
+			t.Parallel()
+
+			out, err := renderOutput(Output{Template: test.template, Path: "out"}, "", embedded, m)
+			require.NoError(t, err)
+
+			for _, expected := range test.expected {
+				assert.Contains(t, out, expected)
+			}
+
+			if !test.wrapped {
+				return
+			}
+
+			for line := range strings.SplitSeq(out, "\n") {
+				assert.LessOrEqual(t, len(line), 80, line)
+			}
+		})
+	}
+}
+
+func TestRenderOutput_ApacheNotice(t *testing.T) {
+	t.Parallel()
+
+	m := Model{Notices: []OutComponent{
+		{
+			Name:        "commons-lang3",
+			Version:     "3.14.0",
+			LicenseIDs:  []string{"Apache-2.0"},
+			Copyright:   "Copyright 2001-2023 The Apache Software Foundation",
+			NoticeTexts: []string{"Apache Commons Lang\nCopyright 2001-2023 The Apache Software Foundation\n\nThis product includes software developed at\nThe Apache Software Foundation (https://www.apache.org/)."},
+		},
+		{Name: "left_pad", LicenseIDs: []string{"MIT"}, Copyright: "Copyright (c) 2014 Foo"},
+	}}
+
+	out, err := renderOutput(Output{Template: "apache-notice", Path: "NOTICE"}, "", embedded, m)
+	require.NoError(t, err)
+
+	assert.Contains(t, out, "This product includes commons-lang3 3.14.0, licensed under Apache-2.0.\n\n"+
+		"Apache Commons Lang\nCopyright 2001-2023 The Apache Software Foundation\n\n"+
+		"This product includes software developed at\nThe Apache Software Foundation (https://www.apache.org/).\n")
+	assert.Equal(t, 1, strings.Count(out, "Copyright 2001-2023"))
+	assert.Contains(t, out, "This product includes left_pad, licensed under MIT.\n\nCopyright (c) 2014 Foo\n")
+}
+
+func TestRenderOutput_CSV(t *testing.T) {
+	t.Parallel()
+
+	m := Model{Components: []OutComponent{
+		{
+			Name:              "github.com/foo/bar",
+			Version:           "v1.0.0",
+			PURL:              "pkg:golang/github.com/foo/bar@v1.0.0",
+			URL:               "https://github.com/foo/bar",
+			LicenseIDs:        []string{"Apache-2.0", "MIT"},
+			LicenseExpression: "MIT OR Apache-2.0",
+			LicenseSource:     "sbom",
+			Copyright:         "Copyright (c) Foo\nCopyright (c) Bar",
+			CopyrightSource:   "LICENSE",
+			LicenseURLs:       map[string]string{"MIT": "https://github.com/foo/bar/blob/main/LICENSE"},
+		},
+		{Name: "font"},
+	}}
+
+	out, err := renderOutput(Output{Template: "csv", Path: "licenses.csv"}, "", embedded, m)
+	require.NoError(t, err)
+
+	records, err := csv.NewReader(strings.NewReader(out)).ReadAll()
+	require.NoError(t, err)
+	assert.Equal(t, [][]string{
+		{"Name", "Version", "PURL", "Ecosystem", "License Expression", "License IDs", "Copyright", "URL", "License Source", "Copyright Source"},
+		{"github.com/foo/bar", "v1.0.0", "pkg:golang/github.com/foo/bar@v1.0.0", "golang", "MIT OR Apache-2.0", "Apache-2.0, MIT", "Copyright (c) Foo\nCopyright (c) Bar", "https://github.com/foo/bar", "sbom", "LICENSE"},
+		{"font", "", "", "other", "", "", "", "", "", ""},
+	}, records)
+
+	out, err = renderOutput(Output{Template: "tsv", Path: "licenses.tsv"}, "", embedded, m)
+	require.NoError(t, err)
+	assert.Contains(t, out, "Name\tVersion\tPURL\t")
+	assert.Contains(t, out, "\nfont\t\t\tother\t\t\t\t\t\t\n")
+
+	out, err = renderOutput(Output{Template: "go-licenses", Path: "licenses.csv"}, "", embedded, m)
+	require.NoError(t, err)
+	assert.Equal(t, "github.com/foo/bar,https://github.com/foo/bar,Apache-2.0\n"+
+		"github.com/foo/bar,https://github.com/foo/bar/blob/main/LICENSE,MIT\n"+
+		"font,Unknown,Unknown\n", out)
+}
+
+func TestModel_LicenseURL(t *testing.T) {
+	t.Parallel()
+
+	bar := OutComponent{Name: "bar", PURL: "pkg:npm/bar@1.0.0", LicenseIDs: []string{"MIT"}, LicenseURLs: map[string]string{"MIT": "https://example.com/bar/LICENSE"}}
+	baz := OutComponent{Name: "baz", PURL: "pkg:npm/baz@1.0.0", LicenseIDs: []string{"MIT"}}
+	qux := OutComponent{Name: "qux", PURL: "pkg:npm/qux@1.0.0", URL: "https://example.com/qux", LicenseIDs: []string{"ISC"}}
+	font := OutComponent{Name: "font", LicenseIDs: []string{"OFL-1.1", "LicenseRef-Font"}}
+
+	m := Model{Licenses: []LicenseBlock{
+		{ID: "MIT", Anchor: "MIT", URL: "https://example.com/bar/LICENSE", UsedBy: []OutComponent{bar, baz}},
+		{ID: "ISC", Anchor: "ISC", UsedBy: []OutComponent{qux}},
+	}}
+
+	assert.Equal(t, "https://example.com/bar/LICENSE", m.LicenseURL(bar, "MIT"))
+	assert.Equal(t, "https://example.com/bar/LICENSE", m.LicenseURL(baz, "MIT"))
+	assert.Equal(t, "https://example.com/qux", m.LicenseURL(qux, "ISC"))
+	assert.Equal(t, "https://spdx.org/licenses/OFL-1.1.html", m.LicenseURL(font, "OFL-1.1"))
+	assert.Empty(t, m.LicenseURL(font, "LicenseRef-Font"))
+}
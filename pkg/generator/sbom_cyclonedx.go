@@ -0,0 +1,168 @@
+package generator
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+// writeCycloneDX returns the input SBOM enriched with the resolved licenses, as
+// a concluded license expression, and copyrights. The filtered out components
+// are removed, along with their dependencies, and the extra components are
+// added. The other fields of the SBOM are kept, and its version is
+// incremented.
+func writeCycloneDX(in inputs, model Model) (string, error) {
+	var doc map[string]any
+
+	dec := json.NewDecoder(bytes.NewReader(in.sbomJSON))
+	dec.UseNumber()
+
+	if err := dec.Decode(&doc); err != nil {
+		return "", fmt.Errorf("failed to decode SBOM: %w", err)
+	}
+
+	// The acknowledgement of licenses appeared in CycloneDX 1.6.
+	acknowledge := compareReleaseNumbers(parseReleaseNumbers(in.sbom.SpecVersion), []int{1, 6}) >= 0
+
+	rawComponents, _ := doc["components"].([]any)
+
+	components := make([]any, 0, len(rawComponents)+len(in.extraComponents))
+	removed := map[string]bool{}
+
+	for i, raw := range rawComponents {
+		obj, ok := raw.(map[string]any)
+		if !ok || i >= len(in.sbom.Components) {
+			components = append(components, raw)
+
+			continue
+		}
+
+		c := in.sbom.Components[i]
+
+		out, ok := model.byKey[componentKey(c)]
+		if !ok {
+			if c.BOMRef != "" {
+				removed[c.BOMRef] = true
+			}
+
+			continue
+		}
+
+		components = append(components, enrichCycloneDXComponent(obj, out, acknowledge))
+	}
+
+	for _, c := range in.extraComponents {
+		out, ok := model.byKey[componentKey(c)]
+		if !ok {
+			continue
+		}
+
+		components = append(components, enrichCycloneDXComponent(extraCycloneDXComponent(c), out, acknowledge))
+	}
+
+	doc["components"] = components
+
+	if deps, ok := doc["dependencies"].([]any); ok {
+		doc["dependencies"] = removeDependencies(deps, removed)
+	}
+
+	if version, ok := doc["version"].(json.Number); ok {
+		if n, err := version.Int64(); err == nil {
+			doc["version"] = n + 1
+		}
+	}
+
+	b, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to encode SBOM: %w", err)
+	}
+
+	return string(b) + "\n", nil
+}
+
+// enrichCycloneDXComponent sets the license expression and the copyright of
+// out on the component obj. The licenses replace the SBOM ones, as CycloneDX
+// does not allow an expression along with other licenses.
+func enrichCycloneDXComponent(obj map[string]any, out OutComponent, acknowledge bool) map[string]any {
+	if out.LicenseExpression != "" {
+		license := map[string]any{"expression": out.LicenseExpression}
+		if acknowledge {
+			license["acknowledgement"] = "concluded"
+		}
+
+		obj["licenses"] = []any{license}
+	}
+
+	if out.Copyright != "" {
+		obj["copyright"] = out.Copyright
+	}
+
+	return obj
+}
+
+// extraCycloneDXComponent returns the CycloneDX component of an extra
+// component, without its licenses and copyright.
+func extraCycloneDXComponent(c Component) map[string]any {
+	obj := map[string]any{
+		"bom-ref": componentKey(c),
+		"type":    c.Type,
+		"name":    c.Name,
+	}
+
+	if c.Version != "" {
+		obj["version"] = c.Version
+	}
+
+	if c.PURL != "" {
+		obj["purl"] = c.PURL
+	}
+
+	if len(c.ExternalReferences) > 0 {
+		refs := make([]any, 0, len(c.ExternalReferences))
+		for _, ref := range c.ExternalReferences {
+			refs = append(refs, map[string]any{"type": ref.Type, "url": ref.URL})
+		}
+
+		obj["externalReferences"] = refs
+	}
+
+	return obj
+}
+
+// removeDependencies removes the removed bom-refs from the dependency graph.
+func removeDependencies(deps []any, removed map[string]bool) []any {
+	if len(removed) == 0 {
+		return deps
+	}
+
+	kept := make([]any, 0, len(deps))
+
+	for _, raw := range deps {
+		dep, ok := raw.(map[string]any)
+		if !ok {
+			kept = append(kept, raw)
+
+			continue
+		}
+
+		if ref, _ := dep["ref"].(string); removed[ref] {
+			continue
+		}
+
+		if dependsOn, ok := dep["dependsOn"].([]any); ok {
+			refs := make([]any, 0, len(dependsOn))
+
+			for _, r := range dependsOn {
+				if ref, _ := r.(string); !removed[ref] {
+					refs = append(refs, r)
+				}
+			}
+
+			dep["dependsOn"] = refs
+		}
+
+		kept = append(kept, dep)
+	}
+
+	return kept
+}
@@ -0,0 +1,99 @@
+package generator
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testCycloneDXSBOM = `{
+  "bomFormat": "CycloneDX",
+  "specVersion": "1.6",
+  "serialNumber": "urn:uuid:3e671687-395b-41f5-a30f-a58921a69b79",
+  "version": 1,
+  "metadata": {"component": {"bom-ref": "root", "type": "application", "name": "traefik"}},
+  "components": [
+    {"bom-ref": "foo", "type": "library", "name": "foo", "version": "1.0.0", "purl": "pkg:npm/foo@1.0.0", "licenses": [{"license": {"name": "Apache 2"}}], "hashes": [{"alg": "SHA-256", "content": "abc"}]},
+    {"bom-ref": "jest", "type": "library", "name": "jest", "version": "29.0.0", "purl": "pkg:npm/jest@29.0.0"}
+  ],
+  "dependencies": [
+    {"ref": "root", "dependsOn": ["foo", "jest"]},
+    {"ref": "jest", "dependsOn": ["foo"]}
+  ]
+}`
+
+// testSBOMInputs returns the inputs and the model of testCycloneDXSBOM, where
+// jest is filtered out and an extra component is added.
+func testSBOMInputs(t *testing.T) (inputs, Model) {
+	t.Helper()
+
+	in := inputs{
+		sbomJSON:   []byte(testCycloneDXSBOM),
+		licenseMap: map[string]string{"Apache 2": "Apache-2.0"},
+		extraComponents: []Component{{
+			Type:               "library",
+			Name:               "font",
+			Version:            "2.0",
+			ExternalReferences: []ExternalReference{{Type: "website", URL: "https://example.com/font"}},
+		}},
+	}
+	require.NoError(t, json.Unmarshal(in.sbomJSON, &in.sbom))
+
+	model := Model{
+		RepoName:    "traefik",
+		GeneratedAt: "2026-01-01T00:00:00Z",
+		Licenses:    []LicenseBlock{{ID: "LicenseRef-Font", Name: "Font License", Text: "Font license text."}},
+		byKey: map[string]OutComponent{
+			"pkg:npm/foo@1.0.0": {Name: "foo", LicenseExpression: "MIT OR Apache-2.0", Copyright: "Copyright (c) Foo"},
+			"font@2.0":          {Name: "font", LicenseExpression: "LicenseRef-Font", URL: "https://example.com/font"},
+		},
+	}
+
+	return in, model
+}
+
+func TestWriteCycloneDX(t *testing.T) {
+	t.Parallel()
+
+	in, model := testSBOMInputs(t)
+
+	out, err := writeCycloneDX(in, model)
+	require.NoError(t, err)
+
+	var doc map[string]any
+	require.NoError(t, json.Unmarshal([]byte(out), &doc))
+
+	assert.Equal(t, "CycloneDX", doc["bomFormat"])
+	assert.InDelta(t, 2, doc["version"], 0)
+
+	components := doc["components"].([]any)
+	require.Len(t, components, 2)
+
+	foo := components[0].(map[string]any)
+	assert.Equal(t, "foo", foo["name"])
+	assert.Equal(t, []any{map[string]any{"expression": "MIT OR Apache-2.0", "acknowledgement": "concluded"}}, foo["licenses"])
+	assert.Equal(t, "Copyright (c) Foo", foo["copyright"])
+	assert.NotNil(t, foo["hashes"])
+
+	font := components[1].(map[string]any)
+	assert.Equal(t, "font@2.0", font["bom-ref"])
+	assert.Equal(t, []any{map[string]any{"expression": "LicenseRef-Font", "acknowledgement": "concluded"}}, font["licenses"])
+	assert.Equal(t, []any{map[string]any{"type": "website", "url": "https://example.com/font"}}, font["externalReferences"])
+
+	assert.Equal(t, []any{map[string]any{"ref": "root", "dependsOn": []any{"foo"}}}, doc["dependencies"])
+}
+
+func TestWriteCycloneDX_NoAcknowledgementBefore16(t *testing.T) {
+	t.Parallel()
+
+	in, model := testSBOMInputs(t)
+	in.sbom.SpecVersion = "1.5"
+
+	out, err := writeCycloneDX(in, model)
+	require.NoError(t, err)
+
+	assert.Contains(t, out, `"expression": "MIT OR Apache-2.0"`)
+	assert.NotContains(t, out, "acknowledgement")
+}
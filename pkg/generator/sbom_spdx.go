@@ -0,0 +1,253 @@
+package generator
+
+import (
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/traefik/assimilis/v2/pkg/version"
+)
+
+// spdxNoAssertion is the SPDX value of an unknown field.
+const spdxNoAssertion = "NOASSERTION"
+
+// spdxDocument is an SPDX 2.3 JSON document.
+type spdxDocument struct {
+	SPDXVersion       string             `json:"spdxVersion"`
+	DataLicense       string             `json:"dataLicense"`
+	SPDXID            string             `json:"SPDXID"`
+	Name              string             `json:"name"`
+	DocumentNamespace string             `json:"documentNamespace"`
+	CreationInfo      spdxCreationInfo   `json:"creationInfo"`
+	Packages          []spdxPackage      `json:"packages"`
+	ExtractedLicenses []spdxExtracted    `json:"hasExtractedLicensingInfos,omitempty"`
+	Relationships     []spdxRelationship `json:"relationships"`
+}
+
+type spdxCreationInfo struct {
+	Created  string   `json:"created"`
+	Creators []string `json:"creators"`
+}
+
+type spdxPackage struct {
+	Name             string            `json:"name"`
+	SPDXID           string            `json:"SPDXID"`
+	VersionInfo      string            `json:"versionInfo,omitempty"`
+	DownloadLocation string            `json:"downloadLocation"`
+	FilesAnalyzed    bool              `json:"filesAnalyzed"`
+	Homepage         string            `json:"homepage,omitempty"`
+	LicenseConcluded string            `json:"licenseConcluded"`
+	LicenseDeclared  string            `json:"licenseDeclared"`
+	CopyrightText    string            `json:"copyrightText"`
+	ExternalRefs     []spdxExternalRef `json:"externalRefs,omitempty"`
+}
+
+type spdxExternalRef struct {
+	ReferenceCategory string `json:"referenceCategory"`
+	ReferenceType     string `json:"referenceType"`
+	ReferenceLocator  string `json:"referenceLocator"`
+}
+
+type spdxExtracted struct {
+	LicenseID     string `json:"licenseId"`
+	Name          string `json:"name,omitempty"`
+	ExtractedText string `json:"extractedText"`
+}
+
+type spdxRelationship struct {
+	Element string `json:"spdxElementId"`
+	Type    string `json:"relationshipType"`
+	Related string `json:"relatedSpdxElement"`
+}
+
+// writeSPDX returns the components of the model as an SPDX 2.3 JSON document:
+// the resolved licenses are the concluded licenses, and the SBOM ones the
+// declared licenses. The document describes the SBOM metadata component, or
+// all the packages when there is none, and the CycloneDX dependency graph
+// becomes DEPENDS_ON relationships.
+func writeSPDX(in inputs, model Model) (string, error) {
+	doc := spdxDocument{
+		SPDXVersion:       "SPDX-2.3",
+		DataLicense:       "CC0-1.0",
+		SPDXID:            "SPDXRef-DOCUMENT",
+		Name:              model.RepoName,
+		DocumentNamespace: spdxNamespace(model.RepoName, in.sbom.SerialNumber),
+		CreationInfo: spdxCreationInfo{
+			Created:  model.GeneratedAt,
+			Creators: []string{"Tool: assimilis-" + version.Version},
+		},
+	}
+
+	// ids holds the SPDX IDs of the packages by bom-ref.
+	ids := map[string]string{}
+	seen := map[string]bool{}
+
+	// licenseTexts holds the license texts shipped by the packages, by ID.
+	licenseTexts := map[string]string{}
+
+	addLicenseTexts := func(texts map[string]string) {
+		for id, text := range texts {
+			if _, ok := licenseTexts[id]; !ok {
+				licenseTexts[id] = text
+			}
+		}
+	}
+
+	for _, c := range slices.Concat(in.sbom.Components, in.extraComponents) {
+		key := componentKey(c)
+
+		out, ok := model.byKey[key]
+		if !ok || seen[key] {
+			continue
+		}
+
+		seen[key] = true
+
+		p := spdxComponentPackage(c, out, in.licenseMap, fmt.Sprintf("SPDXRef-Package-%d", len(doc.Packages)+1))
+		doc.Packages = append(doc.Packages, p)
+		addLicenseTexts(out.LicenseTexts)
+
+		if c.BOMRef != "" {
+			ids[c.BOMRef] = p.SPDXID
+		}
+	}
+
+	if root := in.sbom.Metadata.Component; root != nil {
+		p := spdxComponentPackage(*root, OutComponent{}, in.licenseMap, "SPDXRef-Root")
+		doc.Packages = append(doc.Packages, p)
+
+		texts, _ := embeddedLicenses(root.Licenses, in.licenseMap)
+		addLicenseTexts(texts)
+
+		if root.BOMRef != "" {
+			ids[root.BOMRef] = p.SPDXID
+		}
+
+		doc.Relationships = append(doc.Relationships, spdxRelationship{Element: doc.SPDXID, Type: "DESCRIBES", Related: p.SPDXID})
+	} else {
+		for _, p := range doc.Packages {
+			doc.Relationships = append(doc.Relationships, spdxRelationship{Element: doc.SPDXID, Type: "DESCRIBES", Related: p.SPDXID})
+		}
+	}
+
+	for _, dep := range in.sbom.Dependencies {
+		id, ok := ids[dep.Ref]
+		if !ok {
+			continue
+		}
+
+		for _, ref := range dep.DependsOn {
+			if related, ok := ids[ref]; ok {
+				doc.Relationships = append(doc.Relationships, spdxRelationship{Element: id, Type: "DEPENDS_ON", Related: related})
+			}
+		}
+	}
+
+	doc.ExtractedLicenses = spdxExtractedLicenses(doc.Packages, model.Licenses, licenseTexts, in.licenseMap)
+
+	b, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to encode SPDX document: %w", err)
+	}
+
+	return string(b) + "\n", nil
+}
+
+// spdxComponentPackage returns the SPDX package of the component c, resolved
+// to out.
+func spdxComponentPackage(c Component, out OutComponent, licenseMap map[string]string, id string) spdxPackage {
+	p := spdxPackage{
+		Name:             c.Name,
+		SPDXID:           id,
+		VersionInfo:      c.Version,
+		DownloadLocation: spdxNoAssertion,
+		Homepage:         out.URL,
+		LicenseConcluded: spdxOrNoAssertion(out.LicenseExpression),
+		LicenseDeclared:  spdxOrNoAssertion(licenseExpression(c.Licenses, normalizeLicenseIDs(c.Licenses, licenseMap), licenseMap)),
+		CopyrightText:    spdxOrNoAssertion(out.Copyright),
+	}
+
+	if c.PURL != "" {
+		p.ExternalRefs = []spdxExternalRef{{ReferenceCategory: "PACKAGE-MANAGER", ReferenceType: "purl", ReferenceLocator: c.PURL}}
+	}
+
+	return p
+}
+
+// spdxExtractedLicenses returns the texts of the LicenseRef- licenses used by
+// the packages, which SPDX requires, from the license blocks. A license only
+// declared in the SBOM has no block: its text is the one shipped by a package,
+// and its name the license-map entry resolving to it.
+func spdxExtractedLicenses(packages []spdxPackage, licenses []LicenseBlock, licenseTexts, licenseMap map[string]string) []spdxExtracted {
+	var extracted []spdxExtracted
+
+	seen := map[string]bool{}
+
+	for _, p := range packages {
+		for _, field := range strings.Fields(p.LicenseConcluded + " " + p.LicenseDeclared) {
+			id := strings.Trim(field, "()")
+			if !strings.HasPrefix(id, "LicenseRef-") || seen[id] {
+				continue
+			}
+
+			seen[id] = true
+
+			e := spdxExtracted{
+				LicenseID:     id,
+				Name:          licenseMapName(id, licenseMap),
+				ExtractedText: spdxOrNoAssertion(normalizeLicenseText(licenseTexts[id])),
+			}
+
+			for _, l := range licenses {
+				if l.ID == id {
+					e.Name, e.ExtractedText = l.Name, l.Text
+
+					break
+				}
+			}
+
+			extracted = append(extracted, e)
+		}
+	}
+
+	return extracted
+}
+
+// licenseMapName returns the first license-map name resolving to id, or id
+// when there is none.
+func licenseMapName(id string, licenseMap map[string]string) string {
+	names := make([]string, 0, len(licenseMap))
+
+	for name, mapped := range licenseMap {
+		if mapped == id && !isLicenseURL(name) && !strings.HasPrefix(name, "LicenseRef-") {
+			names = append(names, name)
+		}
+	}
+
+	if len(names) == 0 {
+		return id
+	}
+
+	return slices.Min(names)
+}
+
+// spdxNamespace returns the document namespace, a unique URI derived from the
+// serial number of the SBOM, or random when it has none.
+func spdxNamespace(name, serialNumber string) string {
+	id := strings.TrimPrefix(serialNumber, "urn:uuid:")
+	if id == "" {
+		id = strings.ToLower(rand.Text())
+	}
+
+	return "https://spdx.org/spdxdocs/" + slugify(name) + "-" + id
+}
+
+func spdxOrNoAssertion(s string) string {
+	if strings.TrimSpace(s) == "" {
+		return spdxNoAssertion
+	}
+
+	return s
+}
@@ -0,0 +1,73 @@
+package generator
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteSPDX(t *testing.T) {
+	t.Parallel()
+
+	in, model := testSBOMInputs(t)
+
+	out, err := writeSPDX(in, model)
+	require.NoError(t, err)
+
+	var doc spdxDocument
+	require.NoError(t, json.Unmarshal([]byte(out), &doc))
+
+	assert.Equal(t, "SPDX-2.3", doc.SPDXVersion)
+	assert.Equal(t, "https://spdx.org/spdxdocs/traefik-3e671687-395b-41f5-a30f-a58921a69b79", doc.DocumentNamespace)
+	assert.Equal(t, "2026-01-01T00:00:00Z", doc.CreationInfo.Created)
+
+	require.Len(t, doc.Packages, 3)
+	assert.Equal(t, spdxPackage{
+		Name:             "foo",
+		SPDXID:           "SPDXRef-Package-1",
+		VersionInfo:      "1.0.0",
+		DownloadLocation: "NOASSERTION",
+		LicenseConcluded: "MIT OR Apache-2.0",
+		LicenseDeclared:  "Apache-2.0",
+		CopyrightText:    "Copyright (c) Foo",
+		ExternalRefs:     []spdxExternalRef{{ReferenceCategory: "PACKAGE-MANAGER", ReferenceType: "purl", ReferenceLocator: "pkg:npm/foo@1.0.0"}},
+	}, doc.Packages[0])
+	assert.Equal(t, "font", doc.Packages[1].Name)
+	assert.Equal(t, "LicenseRef-Font", doc.Packages[1].LicenseConcluded)
+	assert.Equal(t, "NOASSERTION", doc.Packages[1].CopyrightText)
+	assert.Equal(t, "SPDXRef-Root", doc.Packages[2].SPDXID)
+
+	assert.Equal(t, []spdxExtracted{{LicenseID: "LicenseRef-Font", Name: "Font License", ExtractedText: "Font license text."}}, doc.ExtractedLicenses)
+
+	assert.Equal(t, []spdxRelationship{
+		{Element: "SPDXRef-DOCUMENT", Type: "DESCRIBES", Related: "SPDXRef-Root"},
+		{Element: "SPDXRef-Root", Type: "DEPENDS_ON", Related: "SPDXRef-Package-1"},
+	}, doc.Relationships)
+}
+
+func TestSPDXExtractedLicenses_NoLicenseBlock(t *testing.T) {
+	t.Parallel()
+
+	packages := []spdxPackage{
+		{LicenseConcluded: "MIT", LicenseDeclared: "(LicenseRef-Acme-EULA OR MIT)"},
+		{LicenseConcluded: "NOASSERTION", LicenseDeclared: "LicenseRef-Unknown"},
+	}
+	licenseTexts := map[string]string{"LicenseRef-Acme-EULA": "Acme EULA\r\n\r\n\r\nAll rights reserved.\n"}
+	licenseMap := map[string]string{"Acme EULA": "LicenseRef-Acme-EULA", "https://acme.example.com/eula": "LicenseRef-Acme-EULA"}
+
+	assert.Equal(t, []spdxExtracted{
+		{LicenseID: "LicenseRef-Acme-EULA", Name: "Acme EULA", ExtractedText: "Acme EULA\n\nAll rights reserved."},
+		{LicenseID: "LicenseRef-Unknown", Name: "LicenseRef-Unknown", ExtractedText: "NOASSERTION"},
+	}, spdxExtractedLicenses(packages, nil, licenseTexts, licenseMap))
+}
+
+func TestSPDXNamespace_Random(t *testing.T) {
+	t.Parallel()
+
+	a, b := spdxNamespace("Traefik Proxy", ""), spdxNamespace("Traefik Proxy", "")
+
+	assert.Contains(t, a, "https://spdx.org/spdxdocs/traefik-proxy-")
+	assert.NotEqual(t, a, b)
+}
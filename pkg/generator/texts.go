@@ -0,0 +1,161 @@
+package generator
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"slices"
+	"strings"
+)
+
+// licenseVariant is one license text shared by a group of components.
+type licenseVariant struct {
+	// Hash identifies a text read from the components' own license files. It is
+	// empty for the SPDX reference text.
+	Hash   string
+	Text   string
+	UsedBy []OutComponent
+}
+
+// componentTextFunc returns the normalized text a component ships for the
+// given license ID, or an empty string if it ships none.
+type componentTextFunc func(c OutComponent, licenseID string) string
+
+// newComponentTextFunc returns a componentTextFunc using the license texts
+// embedded in the SBOM, or reading license files from the local package caches.
+// When a component ships several license files, the detector picks the one
+// matching the license ID; without a detector, a file is only used for
+// components with a single license.
+func newComponentTextFunc(enricher copyrightEnricher, detector *licenseDetector) componentTextFunc {
+	cache := map[string][]licenseFile{}
+
+	return func(c OutComponent, licenseID string) string {
+		// A text embedded in the SBOM is the component's own.
+		if t := c.LicenseTexts[licenseID]; t != "" {
+			return normalizeLicenseText(t)
+		}
+
+		if c.PURL == "" {
+			return ""
+		}
+
+		files, ok := cache[c.PURL]
+		if !ok {
+			files = enricher.licenseFiles(c.PURL)
+			cache[c.PURL] = files
+		}
+
+		if len(files) == 0 {
+			return ""
+		}
+
+		if detector != nil {
+			for _, f := range files {
+				if slices.Contains(detector.detect([]licenseFile{f}), licenseID) {
+					return normalizeLicenseText(f.Text)
+				}
+			}
+		}
+
+		if len(c.LicenseIDs) == 1 {
+			return normalizeLicenseText(files[0].Text)
+		}
+
+		return ""
+	}
+}
+
+// newExtraComponentTextFunc returns a componentTextFunc using the license files
+// of the extra components only: they are the texts the project ships with, even
+// when the components do not ship theirs.
+func newExtraComponentTextFunc(extras []Component) componentTextFunc {
+	keys := map[string]bool{}
+	for _, c := range extras {
+		keys[componentKey(c)] = true
+	}
+
+	return func(c OutComponent, licenseID string) string {
+		if !keys[outComponentKey(c)] {
+			return ""
+		}
+
+		return normalizeLicenseText(c.LicenseTexts[licenseID])
+	}
+}
+
+// groupLicenseVariants groups the components using a license by the text they
+// ship for it. Components without their own text share the SPDX variant, which
+// comes first. comps must be sorted; variants keep that order.
+func groupLicenseVariants(licenseID string, comps []OutComponent, textOf componentTextFunc) []licenseVariant {
+	if textOf == nil {
+		return []licenseVariant{{UsedBy: comps}}
+	}
+
+	spdx := licenseVariant{}
+
+	var variants []licenseVariant
+
+	byHash := map[string]int{}
+
+	for _, c := range comps {
+		text := textOf(c, licenseID)
+		if text == "" {
+			spdx.UsedBy = append(spdx.UsedBy, c)
+
+			continue
+		}
+
+		hash := licenseTextHash(text)
+
+		if i, ok := byHash[hash]; ok {
+			variants[i].UsedBy = append(variants[i].UsedBy, c)
+
+			continue
+		}
+
+		byHash[hash] = len(variants)
+		variants = append(variants, licenseVariant{Hash: hash, Text: text, UsedBy: []OutComponent{c}})
+	}
+
+	if len(spdx.UsedBy) > 0 {
+		variants = append([]licenseVariant{spdx}, variants...)
+	}
+
+	return variants
+}
+
+// normalizeLicenseText normalizes line endings and whitespace: trailing spaces
+// are trimmed, runs of blank lines are collapsed, and leading and trailing blank
+// lines are removed.
+func normalizeLicenseText(text string) string {
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+
+	var lines []string
+
+	blank := false
+
+	for line := range strings.SplitSeq(text, "\n") {
+		line = strings.TrimRight(line, " \t\r")
+		if line == "" {
+			blank = len(lines) > 0
+
+			continue
+		}
+
+		if blank {
+			lines = append(lines, "")
+			blank = false
+		}
+
+		lines = append(lines, line)
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// licenseTextHash returns a short hash of text that ignores whitespace, so the
+// same license reflowed differently still groups together.
+func licenseTextHash(text string) string {
+	sum := sha256.Sum256([]byte(strings.Join(strings.Fields(text), " ")))
+
+	return hex.EncodeToString(sum[:])[:12]
+}
@@ -0,0 +1,140 @@
+package generator
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizeLicenseText(t *testing.T) {
+	t.Parallel()
+
+	in := "\r\n\r\nMIT License  \r\n\r\n\r\n\r\nCopyright (c) Foo\t\r\n\n\n"
+	assert.Equal(t, "MIT License\n\nCopyright (c) Foo", normalizeLicenseText(in))
+}
+
+func TestLicenseTextHash_IgnoresWhitespace(t *testing.T) {
+	t.Parallel()
+
+	a := licenseTextHash("Permission is hereby granted,\nfree of charge")
+	b := licenseTextHash("Permission  is hereby\tgranted, free of\n\ncharge")
+
+	assert.Equal(t, a, b)
+	assert.Len(t, a, 12)
+	assert.NotEqual(t, a, licenseTextHash("Permission is hereby granted"))
+}
+
+func TestGroupLicenseVariants(t *testing.T) {
+	t.Parallel()
+
+	comps := []OutComponent{{Name: "a"}, {Name: "b"}, {Name: "c"}, {Name: "d"}}
+	texts := map[string]string{"a": "Copyright Foo", "c": "Copyright  Foo", "d": "Copyright Bar"}
+
+	variants := groupLicenseVariants("MIT", comps, func(c OutComponent, _ string) string {
+		return texts[c.Name]
+	})
+
+	require.Len(t, variants, 3)
+
+	assert.Empty(t, variants[0].Hash)
+	assert.Equal(t, []OutComponent{{Name: "b"}}, variants[0].UsedBy)
+
+	assert.Equal(t, licenseTextHash("Copyright Foo"), variants[1].Hash)
+	assert.Equal(t, "Copyright Foo", variants[1].Text)
+	assert.Equal(t, []OutComponent{{Name: "a"}, {Name: "c"}}, variants[1].UsedBy)
+
+	assert.Equal(t, []OutComponent{{Name: "d"}}, variants[2].UsedBy)
+}
+
+func TestGroupLicenseVariants_Disabled(t *testing.T) {
+	t.Parallel()
+
+	comps := []OutComponent{{Name: "a"}, {Name: "b"}}
+
+	assert.Equal(t, []licenseVariant{{UsedBy: comps}}, groupLicenseVariants("MIT", comps, nil))
+}
+
+func TestComponentTextFunc(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	for name, text := range map[string]string{"single": "Copyright (c) Single\r\n", "dual": "Copyright (c) Dual"} {
+		require.NoError(t, os.MkdirAll(filepath.Join(dir, name), 0o755))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name, "LICENSE"), []byte(text), 0o644))
+	}
+
+	textOf := newComponentTextFunc(copyrightEnricher{nodeModulesDir: dir}, nil)
+
+	single := OutComponent{PURL: "pkg:npm/single@1.0.0", LicenseIDs: []string{"MIT"}}
+	assert.Equal(t, "Copyright (c) Single", textOf(single, "MIT"))
+
+	// Without a detector, the file cannot be attributed to one of several licenses.
+	dual := OutComponent{PURL: "pkg:npm/dual@1.0.0", LicenseIDs: []string{"Apache-2.0", "MIT"}}
+	assert.Empty(t, textOf(dual, "MIT"))
+
+	assert.Empty(t, textOf(OutComponent{PURL: "pkg:npm/missing@1.0.0", LicenseIDs: []string{"MIT"}}, "MIT"))
+}
+
+func TestBuildLicenseBlocks_ComponentTexts(t *testing.T) {
+	t.Parallel()
+
+	byLicense := map[string][]OutComponent{
+		"BSD-3-Clause": {{Name: "b"}, {Name: "a"}},
+	}
+	texts := map[string]string{"a": "Copyright A", "b": "Copyright B"}
+
+	licensesDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(licensesDir, "BSD-3-Clause.txt"), []byte("BSD 3-Clause License"), 0o644))
+
+	blocks, err := buildLicenseBlocks(context.Background(), Config{OutLicensesDir: licensesDir}, byLicense, nil, func(c OutComponent, _ string) string {
+		return texts[c.Name]
+	})
+	require.NoError(t, err)
+	require.Len(t, blocks, 2)
+
+	assert.Equal(t, "BSD-3-Clause-"+licenseTextHash("Copyright A"), blocks[0].Anchor)
+	assert.Equal(t, "Copyright A", blocks[0].Text)
+	assert.Equal(t, "BSD-3-Clause-"+licenseTextHash("Copyright B"), blocks[1].Anchor)
+
+	overview := buildOverview(blocks)
+	require.Len(t, overview, 1)
+	assert.Equal(t, OverviewItem{ID: "BSD-3-Clause", Name: "BSD-3-Clause", Anchor: blocks[0].Anchor, Count: 2}, overview[0])
+}
+
+func TestBuildLicenseBlocks_ComponentTextsUnknownLicense(t *testing.T) {
+	t.Parallel()
+
+	// The ID is validated even though every component ships its own text.
+	byLicense := map[string][]OutComponent{
+		"Foo-1.0":           {{Name: "a"}},
+		"LicenseRef-Custom": {{Name: "b"}},
+	}
+
+	// A canceled context makes the SPDX text unavailable without a request.
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	cfg := Config{OutLicensesDir: t.TempDir(), SPDXVersion: "v3.27.0"}
+
+	_, err := buildLicenseBlocks(ctx, cfg, byLicense, nil, func(c OutComponent, _ string) string {
+		return "Copyright " + c.Name
+	})
+
+	var unknown UnknownLicensesError
+	require.ErrorAs(t, err, &unknown)
+	assert.Equal(t, []string{"Foo-1.0"}, unknown.IDs)
+}
+
+func TestNewComponentTextFunc_EmbeddedText(t *testing.T) {
+	t.Parallel()
+
+	textOf := newComponentTextFunc(copyrightEnricher{}, nil)
+
+	c := OutComponent{Name: "foo", LicenseIDs: []string{"Apache-2.0", "MIT"}, LicenseTexts: map[string]string{"MIT": "Copyright (c) Foo\r\n\r\n\r\nPermission is hereby granted...  "}}
+	assert.Equal(t, "Copyright (c) Foo\n\nPermission is hereby granted...", textOf(c, "MIT"))
+	assert.Empty(t, textOf(c, "Apache-2.0"))
+}
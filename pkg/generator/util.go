@@ -59,3 +59,21 @@ func sanitizeID(s string) string {
 
 	return strings.Trim(s, "-")
 }
+
+// mergeMissing adds the entries of src missing from dst, allocating dst if
+// needed.
+func mergeMissing(dst, src map[string]string) map[string]string {
+	for k, v := range src {
+		if _, ok := dst[k]; ok {
+			continue
+		}
+
+		if dst == nil {
+			dst = make(map[string]string, len(src))
+		}
+
+		dst[k] = v
+	}
+
+	return dst
+}
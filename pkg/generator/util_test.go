@@ -9,6 +9,16 @@ import (
 	"github.com/stretchr/testify/require"
 )
 
+// writeTestFile writes content to the file name, a slash-separated path
+// relative to dir, creating its parent directories.
+func writeTestFile(t *testing.T, dir, name, content string) {
+	t.Helper()
+
+	p := filepath.Join(dir, filepath.FromSlash(name))
+	require.NoError(t, os.MkdirAll(filepath.Dir(p), 0o755))
+	require.NoError(t, os.WriteFile(p, []byte(content), 0o644))
+}
+
 func TestUniqSorted(t *testing.T) {
 	t.Parallel()
 
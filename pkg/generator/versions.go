@@ -0,0 +1,363 @@
+package generator
+
+import (
+	"cmp"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var (
+	// versionComparatorRegex matches a comparator of a version constraint, e.g.
+	// ">= 1.2.0", "<v2", "~=2.2" or "^1.4.0".
+	versionComparatorRegex = regexp.MustCompile(`(==|!=|<=|>=|~=|=|<|>|\^|~)?\s*([^\s,<>=!~^|][^\s,<>=~^|]*)`)
+
+	// semverRegex matches a semantic version, with a "v" prefix for Go modules
+	// or a "go" prefix for the Go standard library.
+	semverRegex = regexp.MustCompile(`^(?:v|go)?(\d+(?:\.\d+)*)(?:-([0-9A-Za-z.-]+))?(?:\+[0-9A-Za-z.-]+)?$`)
+)
+
+// versionConstraint is a parsed version constraint: alternatives separated by
+// "||", each one a list of comparators that must all match.
+type versionConstraint struct {
+	pep440       bool
+	alternatives [][]versionComparator
+}
+
+type versionComparator struct {
+	op      string
+	version string
+}
+
+// parseVersionConstraint parses a version constraint for the versions of the
+// given PURL type. Comparators are separated by commas or spaces, and
+// alternatives by "||":
+//
+//	">= 1.2.0, < 2.0.0", "^1.2.0", "~1.2.0", "< 1.6.0 || >= 2.0.0"
+//
+// PyPI versions follow PEP 440 (including "~=" and "!="), other versions follow
+// semantic versioning, which covers Go pseudo-versions. An empty constraint or
+// "*" matches all versions.
+func parseVersionConstraint(purlType, constraint string) (versionConstraint, error) {
+	c := versionConstraint{pep440: purlType == "pypi"}
+
+	constraint = strings.TrimSpace(constraint)
+	if constraint == "" || constraint == "*" {
+		return c, nil
+	}
+
+	for alternative := range strings.SplitSeq(constraint, "||") {
+		var comparators []versionComparator
+
+		for _, m := range versionComparatorRegex.FindAllStringSubmatch(alternative, -1) {
+			op := m[1]
+			if op == "" || op == "==" {
+				op = "="
+			}
+
+			if _, ok := c.parse(m[2]); !ok {
+				return versionConstraint{}, fmt.Errorf("invalid version %q in constraint %q", m[2], constraint)
+			}
+
+			comparators = append(comparators, versionComparator{op: op, version: m[2]})
+		}
+
+		if len(comparators) == 0 {
+			return versionConstraint{}, fmt.Errorf("invalid constraint %q", constraint)
+		}
+
+		c.alternatives = append(c.alternatives, comparators)
+	}
+
+	return c, nil
+}
+
+// matches reports whether the version satisfies the constraint. Versions that
+// cannot be parsed only match an empty constraint.
+func (c versionConstraint) matches(version string) bool {
+	if len(c.alternatives) == 0 {
+		return true
+	}
+
+	v, ok := c.parse(version)
+	if !ok {
+		return false
+	}
+
+	for _, comparators := range c.alternatives {
+		if c.matchesAll(v, comparators) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (c versionConstraint) matchesAll(v parsedVersion, comparators []versionComparator) bool {
+	for _, comparator := range comparators {
+		bound, _ := c.parse(comparator.version)
+		order := v.compare(bound)
+
+		var ok bool
+
+		switch comparator.op {
+		case "=":
+			ok = order == 0
+		case "!=":
+			ok = order != 0
+		case "<":
+			ok = order < 0
+		case "<=":
+			ok = order <= 0
+		case ">":
+			ok = order > 0
+		case ">=":
+			ok = order >= 0
+		case "^", "~", "~=":
+			upper, _ := c.parse(upperBound(comparator.op, bound.releaseNumbers()))
+			ok = order >= 0 && v.compare(upper) < 0
+		}
+
+		if !ok {
+			return false
+		}
+	}
+
+	return true
+}
+
+// upperBound returns the exclusive upper bound of a caret ("^1.2.3" is
+// "<2.0.0", "^0.2.3" is "<0.3.0"), tilde ("~1.2.3" is "<1.3.0") or compatible
+// release ("~=1.4.5" is "<1.5", "~=2.2" is "<3") comparator.
+func upperBound(op string, release []int) string {
+	release = append([]int(nil), release...)
+
+	var idx int
+
+	switch op {
+	case "^":
+		for idx < len(release)-1 && release[idx] == 0 {
+			idx++
+		}
+	case "~":
+		if len(release) > 1 {
+			idx = 1
+		}
+	case "~=":
+		idx = max(len(release)-2, 0)
+	}
+
+	release = release[:idx+1]
+	release[idx]++
+
+	parts := make([]string, len(release))
+	for i, n := range release {
+		parts[i] = strconv.Itoa(n)
+	}
+
+	return strings.Join(parts, ".")
+}
+
+func (c versionConstraint) parse(version string) (parsedVersion, bool) {
+	if c.pep440 {
+		return parsePEP440(version)
+	}
+
+	return parseSemver(version)
+}
+
+// parsedVersion is a version comparable with another version of the same
+// scheme.
+type parsedVersion interface {
+	compare(other parsedVersion) int
+	releaseNumbers() []int
+}
+
+// semver is a semantic version. The build metadata (e.g. "+incompatible") does
+// not take part in comparisons. Missing minor and patch numbers are zero.
+type semver struct {
+	release    []int
+	prerelease []string
+}
+
+func parseSemver(version string) (parsedVersion, bool) {
+	m := semverRegex.FindStringSubmatch(strings.TrimSpace(version))
+	if m == nil {
+		return nil, false
+	}
+
+	v := semver{release: parseReleaseNumbers(m[1])}
+	if m[2] != "" {
+		v.prerelease = strings.Split(m[2], ".")
+	}
+
+	return v, true
+}
+
+func (v semver) releaseNumbers() []int {
+	return v.release
+}
+
+func (v semver) compare(other parsedVersion) int {
+	o := other.(semver)
+
+	if c := compareReleaseNumbers(v.release, o.release); c != 0 {
+		return c
+	}
+
+	// A version without prerelease has a higher precedence.
+	switch {
+	case len(v.prerelease) == 0 && len(o.prerelease) == 0:
+		return 0
+	case len(v.prerelease) == 0:
+		return 1
+	case len(o.prerelease) == 0:
+		return -1
+	}
+
+	for i := range min(len(v.prerelease), len(o.prerelease)) {
+		if c := comparePrereleaseIdentifiers(v.prerelease[i], o.prerelease[i]); c != 0 {
+			return c
+		}
+	}
+
+	return cmp.Compare(len(v.prerelease), len(o.prerelease))
+}
+
+// comparePrereleaseIdentifiers compares identifiers numerically when both are
+// numeric; numeric identifiers have a lower precedence than alphanumeric ones.
+func comparePrereleaseIdentifiers(a, b string) int {
+	na, errA := strconv.Atoi(a)
+	nb, errB := strconv.Atoi(b)
+
+	switch {
+	case errA == nil && errB == nil:
+		return cmp.Compare(na, nb)
+	case errA == nil:
+		return -1
+	case errB == nil:
+		return 1
+	default:
+		return strings.Compare(a, b)
+	}
+}
+
+// pep440Version is a PyPI version. Missing segments are represented by
+// sentinels so that the PEP 440 ordering applies: "1.0.dev1" < "1.0a1" <
+// "1.0" < "1.0.post1".
+type pep440Version struct {
+	epoch   int
+	release []int
+	pre     [2]int
+	post    int
+	dev     int
+}
+
+const (
+	pep440Min = -1 << 31
+	pep440Max = 1<<31 - 1
+)
+
+func parsePEP440(version string) (parsedVersion, bool) {
+	m := pep440Regex.FindStringSubmatch(strings.ToLower(strings.TrimSpace(version)))
+	if m == nil {
+		return nil, false
+	}
+
+	v := pep440Version{
+		release: parseReleaseNumbers(m[2]),
+		pre:     [2]int{pep440Max, 0},
+		post:    pep440Min,
+		dev:     pep440Max,
+	}
+
+	v.epoch, _ = strconv.Atoi(m[1])
+
+	if m[3] != "" {
+		n, _ := strconv.Atoi(m[4])
+
+		switch m[3] {
+		case "a", "alpha":
+			v.pre = [2]int{0, n}
+		case "b", "beta":
+			v.pre = [2]int{1, n}
+		default:
+			v.pre = [2]int{2, n}
+		}
+	}
+
+	switch {
+	case m[5] != "":
+		v.post, _ = strconv.Atoi(m[5])
+	case m[6] != "":
+		v.post, _ = strconv.Atoi(m[7])
+	}
+
+	if m[8] != "" {
+		v.dev, _ = strconv.Atoi(m[9])
+
+		// A developmental release of a final release sorts before its
+		// pre-releases.
+		if m[3] == "" && v.post == pep440Min {
+			v.pre = [2]int{pep440Min, 0}
+		}
+	}
+
+	return v, true
+}
+
+func (v pep440Version) releaseNumbers() []int {
+	return v.release
+}
+
+func (v pep440Version) compare(other parsedVersion) int {
+	o := other.(pep440Version)
+
+	for _, c := range []int{
+		cmp.Compare(v.epoch, o.epoch),
+		compareReleaseNumbers(v.release, o.release),
+		cmp.Compare(v.pre[0], o.pre[0]),
+		cmp.Compare(v.pre[1], o.pre[1]),
+		cmp.Compare(v.post, o.post),
+		cmp.Compare(v.dev, o.dev),
+	} {
+		if c != 0 {
+			return c
+		}
+	}
+
+	return 0
+}
+
+func parseReleaseNumbers(s string) []int {
+	parts := strings.Split(s, ".")
+	release := make([]int, len(parts))
+
+	for i, p := range parts {
+		release[i], _ = strconv.Atoi(p)
+	}
+
+	return release
+}
+
+// compareReleaseNumbers compares release numbers, missing numbers being zero:
+// "1.2" equals "1.2.0".
+func compareReleaseNumbers(a, b []int) int {
+	for i := range max(len(a), len(b)) {
+		var x, y int
+		if i < len(a) {
+			x = a[i]
+		}
+
+		if i < len(b) {
+			y = b[i]
+		}
+
+		if c := cmp.Compare(x, y); c != 0 {
+			return c
+		}
+	}
+
+	return 0
+}
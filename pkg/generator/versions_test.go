@@ -0,0 +1,106 @@
+package generator
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVersionConstraint_Semver(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		constraint string
+		version    string
+		expected   bool
+	}{
+		{constraint: "", version: "1.0.0", expected: true},
+		{constraint: "*", version: "anything", expected: true},
+		{constraint: "1.2.3", version: "v1.2.3", expected: true},
+		{constraint: "= 1.2.3", version: "1.2.4", expected: false},
+		{constraint: ">= 1.2.0, < 2.0.0", version: "1.9.9", expected: true},
+		{constraint: ">=1.2.0 <2.0.0", version: "2.0.0", expected: false},
+		{constraint: "< 2.0.0", version: "2.0.0-rc.1", expected: true},
+		{constraint: "> 1.0.0-alpha.2", version: "1.0.0-alpha.10", expected: true},
+		{constraint: "> 1.0.0-alpha", version: "1.0.0-alpha.1", expected: true},
+		{constraint: "> 1.0.0-2", version: "1.0.0-beta", expected: true},
+		{constraint: "= 1.2", version: "1.2.0", expected: true},
+		{constraint: "= 2.0.0", version: "v2.0.0+incompatible", expected: true},
+		{constraint: "^1.2.3", version: "1.9.0", expected: true},
+		{constraint: "^1.2.3", version: "2.0.0", expected: false},
+		{constraint: "^0.2.3", version: "0.2.9", expected: true},
+		{constraint: "^0.2.3", version: "0.3.0", expected: false},
+		{constraint: "~1.2.3", version: "1.2.9", expected: true},
+		{constraint: "~1.2.3", version: "1.3.0", expected: false},
+		{constraint: "!= 1.5.0", version: "1.5.0", expected: false},
+		{constraint: "< 1.0.0 || >= 2.0.0", version: "2.1.0", expected: true},
+		{constraint: "< 1.0.0 || >= 2.0.0", version: "1.5.0", expected: false},
+		// Go pseudo-versions sort between the previous and the next release.
+		{constraint: "> v1.5.7, < v1.5.8", version: "v1.5.8-0.20230901120000-0123456789ab", expected: true},
+		{constraint: "< v0.0.0-20230101000000-000000000000", version: "v0.0.0-20221231000000-abcdef012345", expected: true},
+		// Go standard library versions.
+		{constraint: ">= go1.22", version: "go1.25.3", expected: true},
+		// Unparsable versions only match empty constraints.
+		{constraint: ">= 1.0.0", version: "latest", expected: false},
+		{constraint: ">= 1.0.0", version: "", expected: false},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.constraint+" "+test.version, func(t *testing.T) {
+			t.Parallel()
+
+			c, err := parseVersionConstraint("golang", test.constraint)
+			require.NoError(t, err)
+
+			assert.Equal(t, test.expected, c.matches(test.version))
+		})
+	}
+}
+
+func TestVersionConstraint_PEP440(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		constraint string
+		version    string
+		expected   bool
+	}{
+		{constraint: "== 1.0", version: "1.0.0", expected: true},
+		{constraint: "< 1.0", version: "1.0rc1", expected: true},
+		{constraint: "< 1.0a1", version: "1.0.dev1", expected: true},
+		{constraint: "> 1.0", version: "1.0.post1", expected: true},
+		{constraint: "> 1.0", version: "1.0-1", expected: true},
+		{constraint: "< 1.0b1", version: "1.0a2", expected: true},
+		{constraint: "< 1.0rc1", version: "1.0c1", expected: false},
+		{constraint: "> 1.0rc1", version: "1.0rc1.post1", expected: true},
+		{constraint: "< 1.0.post1", version: "1.0.post1.dev0", expected: true},
+		{constraint: "< 1!0.1", version: "2.0", expected: true},
+		{constraint: "== 1.0", version: "1.0+local.1", expected: true},
+		{constraint: "~= 2.2", version: "2.9", expected: true},
+		{constraint: "~= 2.2", version: "3.0", expected: false},
+		{constraint: "~= 1.4.5", version: "1.4.9", expected: true},
+		{constraint: "~= 1.4.5", version: "1.5.0", expected: false},
+		{constraint: ">= 2.0, != 2.1", version: "2.1.0", expected: false},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.constraint+" "+test.version, func(t *testing.T) {
+			t.Parallel()
+
+			c, err := parseVersionConstraint("pypi", test.constraint)
+			require.NoError(t, err)
+
+			assert.Equal(t, test.expected, c.matches(test.version))
+		})
+	}
+}
+
+func TestParseVersionConstraint_Invalid(t *testing.T) {
+	t.Parallel()
+
+	for _, constraint := range []string{">= one", "<", "^ || 1.0"} {
+		_, err := parseVersionConstraint("npm", constraint)
+		assert.Error(t, err, constraint)
+	}
+}